@@ -0,0 +1,204 @@
+// Package batch drives a phylum's scheduled batch requests (as queued by
+// the phylum-side batch: library's batch:schedule-request) from the Go
+// side: polling for requests that have come due, invoking a registered
+// Go handler for each, and reporting the result back so the phylum's own
+// batch:handler callback fires with the outcome.
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/luthersystems/substratecommon"
+	"github.com/luthersystems/substratecommon/substratewrapper"
+)
+
+// well-known endpoints the batch: phylum library exposes for polling due
+// requests and reporting their outcome back.
+const (
+	pollEndpoint   = "batch_poll"
+	reportEndpoint = "batch_report"
+)
+
+// HandlerFunc processes a single due batch request and returns the
+// result to report back to the phylum, or an error if it couldn't be
+// processed.
+type HandlerFunc func(batchID string, requestID string, message json.RawMessage) (json.RawMessage, error)
+
+// Driver polls and dispatches scheduled batch requests for a substrate
+// instance. A single Driver can back any number of Register'd batch
+// names.
+type Driver struct {
+	client      *substratewrapper.SubstrateInstanceWrapperCommon
+	log         *logrus.Logger
+	logFields   logrus.Fields
+	retryPolicy *RetryPolicy
+	deadLetter  DeadLetterFunc
+	clock       func() time.Time
+}
+
+// DriverOption configures a Driver at construction time.
+type DriverOption func(*Driver)
+
+// NewDriver constructs a Driver for client, which is used to poll for and
+// report on every batch name later Register'd on it.
+func NewDriver(client *substratewrapper.SubstrateInstanceWrapperCommon, opts ...DriverOption) *Driver {
+	d := &Driver{
+		client: client,
+		log:    logrus.StandardLogger(),
+		clock:  time.Now,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// WithLog sets the logger a Driver reports polling/reporting failures to.
+func WithLog(log *logrus.Logger) DriverOption {
+	return func(d *Driver) {
+		d.log = log
+	}
+}
+
+// WithLogField adds a field included on every log entry a Driver emits.
+func WithLogField(key string, value interface{}) DriverOption {
+	return func(d *Driver) {
+		if d.logFields == nil {
+			d.logFields = logrus.Fields{}
+		}
+		d.logFields[key] = value
+	}
+}
+
+func (d *Driver) logger() *logrus.Entry {
+	return d.log.WithFields(d.logFields)
+}
+
+func (d *Driver) now() time.Time {
+	return d.clock()
+}
+
+// Ticker drains and dispatches one batch name's due requests each time
+// its Tick method is called, optionally also on its own every interval
+// (see Register).
+type Ticker struct {
+	driver    *Driver
+	batchName string
+	handler   HandlerFunc
+	configs   []substratecommon.Config
+
+	mu      sync.Mutex
+	pending map[string]*retryState
+}
+
+// Register starts tracking batchName on d: every interval (if non-zero)
+// it automatically drains due requests for batchName until ctx is done,
+// invoking handler for each and reporting its result back to the phylum.
+// Callers can also drive it manually via the returned Ticker's Tick
+// method, which is how tests exercise it deterministically. configs are
+// included on every poll/report call this registration makes, the same
+// way they'd be passed to client.CallCtx directly.
+func (d *Driver) Register(ctx context.Context, batchName string, interval time.Duration, handler HandlerFunc, configs ...substratecommon.Config) *Ticker {
+	t := &Ticker{
+		driver:    d,
+		batchName: batchName,
+		handler:   handler,
+		configs:   configs,
+		pending:   make(map[string]*retryState),
+	}
+	if interval > 0 {
+		go t.loop(ctx, interval)
+	}
+	return t
+}
+
+func (t *Ticker) loop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := t.Tick(ctx); err != nil {
+				t.driver.logger().WithError(err).Warnf("batch: automatic tick of %q failed", t.batchName)
+			}
+		}
+	}
+}
+
+// Tick polls for requests due on this Ticker's batch name and processes
+// each of them in turn, returning the first error encountered (if any)
+// after attempting them all.
+func (t *Ticker) Tick(ctx context.Context) error {
+	items, err := t.poll(ctx)
+	if err != nil {
+		return err
+	}
+	var firstErr error
+	for _, item := range items {
+		if err := t.process(ctx, item); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// polledItem is one due request returned by pollEndpoint.
+type polledItem struct {
+	BatchID   string          `json:"batch_id"`
+	RequestID string          `json:"request_id"`
+	Message   json.RawMessage `json:"message"`
+}
+
+func (t *Ticker) poll(ctx context.Context) ([]polledItem, error) {
+	resp, err := (*t.driver.client).CallCtx(ctx, pollEndpoint, withExtra(t.configs, substratecommon.WithParams(t.batchName))...)
+	if err != nil {
+		return nil, fmt.Errorf("batch: poll %s: %w", t.batchName, err)
+	}
+	if resp.HasError {
+		return nil, fmt.Errorf("batch: poll %s: %s (code %d)", t.batchName, resp.ErrorMessage, resp.ErrorCode)
+	}
+	var items []polledItem
+	if len(resp.ResultJSON) > 0 {
+		if err := json.Unmarshal(resp.ResultJSON, &items); err != nil {
+			return nil, fmt.Errorf("batch: poll %s: decoding response: %w", t.batchName, err)
+		}
+	}
+	return items, nil
+}
+
+type reportParams struct {
+	BatchID   string          `json:"batch_id"`
+	RequestID string          `json:"request_id"`
+	Result    json.RawMessage `json:"result,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+func (t *Ticker) report(ctx context.Context, item polledItem, result json.RawMessage, handlerErr error) error {
+	params := reportParams{BatchID: item.BatchID, RequestID: item.RequestID, Result: result}
+	if handlerErr != nil {
+		params.Error = handlerErr.Error()
+	}
+	resp, err := (*t.driver.client).CallCtx(ctx, reportEndpoint, withExtra(t.configs, substratecommon.WithParams(params))...)
+	if err != nil {
+		return fmt.Errorf("batch: report %s/%s: %w", item.BatchID, item.RequestID, err)
+	}
+	if resp.HasError {
+		return fmt.Errorf("batch: report %s/%s: %s (code %d)", item.BatchID, item.RequestID, resp.ErrorMessage, resp.ErrorCode)
+	}
+	return nil
+}
+
+func withExtra(base []substratecommon.Config, extra ...substratecommon.Config) []substratecommon.Config {
+	out := make([]substratecommon.Config, 0, len(base)+len(extra))
+	out = append(out, base...)
+	out = append(out, extra...)
+	return out
+}
@@ -0,0 +1,150 @@
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy is an exponential backoff policy for a Driver's Register'd
+// handlers: a failing request is retried on subsequent ticks with a delay
+// of InitialInterval * Multiplier^attempt, jittered by +/-
+// RandomizationFactor and capped at MaxInterval, until MaxAttempts or
+// MaxElapsedTime is reached.
+type RetryPolicy struct {
+	InitialInterval     time.Duration
+	MaxInterval         time.Duration
+	Multiplier          float64
+	RandomizationFactor float64
+	MaxElapsedTime      time.Duration
+	MaxAttempts         int
+}
+
+func (p RetryPolicy) exhausted(attempt int, elapsed time.Duration) bool {
+	if p.MaxAttempts > 0 && attempt >= p.MaxAttempts {
+		return true
+	}
+	if p.MaxElapsedTime > 0 && elapsed >= p.MaxElapsedTime {
+		return true
+	}
+	return false
+}
+
+func (p RetryPolicy) nextDelay(attempt int) time.Duration {
+	interval := p.InitialInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	multiplier := p.Multiplier
+	if multiplier <= 0 {
+		multiplier = 1
+	}
+	delay := float64(interval) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxInterval > 0 && delay > float64(p.MaxInterval) {
+		delay = float64(p.MaxInterval)
+	}
+	if p.RandomizationFactor > 0 {
+		delta := delay * p.RandomizationFactor
+		delay = delay - delta + rand.Float64()*2*delta
+	}
+	if delay < 0 {
+		delay = 0
+	}
+	return time.Duration(delay)
+}
+
+// DeadLetterFunc receives a batch request whose retry policy has been
+// exhausted, in addition to (not instead of) the failure being reported
+// to the phylum as usual.
+type DeadLetterFunc func(batchID string, requestID string, message json.RawMessage, lastErr error)
+
+// WithRetry installs policy as the retry policy for every handler
+// Register'd on this Driver. Without it, a handler gets exactly one
+// attempt and its error is reported to the phylum immediately.
+func WithRetry(policy RetryPolicy) DriverOption {
+	return func(d *Driver) {
+		d.retryPolicy = &policy
+	}
+}
+
+// WithDeadLetter registers fn to be called for requests whose retry
+// policy has been exhausted.
+func WithDeadLetter(fn DeadLetterFunc) DriverOption {
+	return func(d *Driver) {
+		d.deadLetter = fn
+	}
+}
+
+// WithClock overrides a Driver's time source, letting tests drive
+// backoff deterministically instead of waiting on the wall clock.
+func WithClock(clock func() time.Time) DriverOption {
+	return func(d *Driver) {
+		d.clock = clock
+	}
+}
+
+// retryState is the attempt bookkeeping for one outstanding request,
+// keyed by batchID+requestID so it survives across ticks.
+type retryState struct {
+	attempt  int
+	firstTry time.Time
+	nextTry  time.Time
+	lastErr  error
+}
+
+// process runs item's handler, honoring any in-progress retry backoff and
+// the Driver's retry policy, and reports to the phylum once the handler
+// succeeds or the policy is exhausted.
+func (t *Ticker) process(ctx context.Context, item polledItem) error {
+	key := item.BatchID + "/" + item.RequestID
+
+	t.mu.Lock()
+	state := t.pending[key]
+	now := t.driver.now()
+	if state != nil && now.Before(state.nextTry) {
+		t.mu.Unlock()
+		return nil
+	}
+	t.mu.Unlock()
+
+	result, herr := t.handler(item.BatchID, item.RequestID, item.Message)
+	if herr == nil {
+		t.mu.Lock()
+		delete(t.pending, key)
+		t.mu.Unlock()
+		return t.report(ctx, item, result, nil)
+	}
+
+	policy := t.driver.retryPolicy
+	if policy == nil {
+		return t.report(ctx, item, nil, herr)
+	}
+
+	t.mu.Lock()
+	if state == nil {
+		state = &retryState{firstTry: now}
+		t.pending[key] = state
+	}
+	state.attempt++
+	state.lastErr = herr
+	exhausted := policy.exhausted(state.attempt, now.Sub(state.firstTry))
+	if !exhausted {
+		state.nextTry = now.Add(policy.nextDelay(state.attempt))
+	}
+	t.mu.Unlock()
+
+	if !exhausted {
+		return nil
+	}
+
+	t.mu.Lock()
+	delete(t.pending, key)
+	t.mu.Unlock()
+
+	if t.driver.deadLetter != nil {
+		t.driver.deadLetter(item.BatchID, item.RequestID, item.Message, herr)
+	}
+	return t.report(ctx, item, nil, herr)
+}
@@ -0,0 +1,206 @@
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/luthersystems/substratecommon"
+	"github.com/luthersystems/substratecommon/substratewrapper"
+)
+
+func TestRetryPolicyExhausted(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 3}
+	if p.exhausted(2, 0) {
+		t.Fatal("expected not exhausted below MaxAttempts")
+	}
+	if !p.exhausted(3, 0) {
+		t.Fatal("expected exhausted once attempt reaches MaxAttempts")
+	}
+
+	p = RetryPolicy{MaxElapsedTime: time.Minute}
+	if p.exhausted(1, 30*time.Second) {
+		t.Fatal("expected not exhausted below MaxElapsedTime")
+	}
+	if !p.exhausted(1, time.Minute) {
+		t.Fatal("expected exhausted once elapsed reaches MaxElapsedTime")
+	}
+}
+
+func TestRetryPolicyNextDelay(t *testing.T) {
+	p := RetryPolicy{InitialInterval: time.Second, Multiplier: 2, MaxInterval: 10 * time.Second}
+	if d := p.nextDelay(1); d != time.Second {
+		t.Fatalf("expected first delay of 1s, got %s", d)
+	}
+	if d := p.nextDelay(2); d != 2*time.Second {
+		t.Fatalf("expected second delay of 2s, got %s", d)
+	}
+	if d := p.nextDelay(10); d != 10*time.Second {
+		t.Fatalf("expected delay capped at MaxInterval, got %s", d)
+	}
+}
+
+// fakeClient implements substratewrapper.SubstrateInstanceWrapperCommon,
+// recording every batch_report call; only CallCtx is meaningful, since
+// process's retry bookkeeping never needs the rest.
+type fakeClient struct {
+	reports []reportParams
+}
+
+func (f *fakeClient) Close() error                   { return nil }
+func (f *fakeClient) CloseCtx(context.Context) error { return nil }
+func (f *fakeClient) HealthCheck(x int) (int, error) { return x, nil }
+func (f *fakeClient) HealthCheckCtx(_ context.Context, x int) (int, error) {
+	return x, nil
+}
+func (f *fakeClient) NewCoherent() substratewrapper.SubstrateInstanceWrapperCommon {
+	return f
+}
+func (f *fakeClient) NewContextCoherent() substratewrapper.SubstrateInstanceWrapperCommon {
+	return f
+}
+func (f *fakeClient) IsTimeoutError(error) bool { return false }
+func (f *fakeClient) Init(string, ...substratecommon.Config) error {
+	return nil
+}
+func (f *fakeClient) InitCtx(context.Context, string, ...substratecommon.Config) error {
+	return nil
+}
+func (f *fakeClient) Call(string, ...substratecommon.Config) (*substratecommon.Response, error) {
+	return nil, errors.New("fakeClient: Call not supported")
+}
+func (f *fakeClient) CallCtx(_ context.Context, method string, configs ...substratecommon.Config) (*substratecommon.Response, error) {
+	if method != reportEndpoint {
+		return nil, errors.New("fakeClient: unexpected method " + method)
+	}
+	opts, err := substratecommon.FlattenOptions(configs...)
+	if err != nil {
+		return nil, err
+	}
+	var params reportParams
+	if err := json.Unmarshal(opts.Params, &params); err != nil {
+		return nil, err
+	}
+	f.reports = append(f.reports, params)
+	return &substratecommon.Response{}, nil
+}
+func (f *fakeClient) QueryInfo(...substratecommon.Config) (uint64, error) {
+	return 0, nil
+}
+func (f *fakeClient) QueryInfoCtx(context.Context, ...substratecommon.Config) (uint64, error) {
+	return 0, nil
+}
+func (f *fakeClient) QueryBlock(uint64, ...substratecommon.Config) (*substratecommon.Block, error) {
+	return nil, nil
+}
+func (f *fakeClient) QueryBlockCtx(context.Context, uint64, ...substratecommon.Config) (*substratecommon.Block, error) {
+	return nil, nil
+}
+func (f *fakeClient) GetLastTransactionID() string { return "" }
+func (f *fakeClient) Upcast() *substratewrapper.SubstrateInstanceWrapperCommon {
+	var common substratewrapper.SubstrateInstanceWrapperCommon = f
+	return &common
+}
+
+func newTestTicker(d *Driver, handler HandlerFunc) *Ticker {
+	return &Ticker{
+		driver:    d,
+		batchName: "test",
+		handler:   handler,
+		pending:   make(map[string]*retryState),
+	}
+}
+
+func TestProcessRetriesThenSucceeds(t *testing.T) {
+	fc := &fakeClient{}
+	var client substratewrapper.SubstrateInstanceWrapperCommon = fc
+	now := time.Unix(0, 0)
+	d := NewDriver(&client, func(d *Driver) {
+		d.clock = func() time.Time { return now }
+	})
+	WithRetry(RetryPolicy{MaxAttempts: 3, InitialInterval: time.Second})(d)
+
+	attempts := 0
+	handler := func(batchID, requestID string, message json.RawMessage) (json.RawMessage, error) {
+		attempts++
+		if attempts < 2 {
+			return nil, errors.New("transient failure")
+		}
+		return json.RawMessage(`"ok"`), nil
+	}
+	tk := newTestTicker(d, handler)
+	item := polledItem{BatchID: "b1", RequestID: "r1"}
+
+	if err := tk.process(context.Background(), item); err != nil {
+		t.Fatalf("process: %s", err)
+	}
+	if len(fc.reports) != 0 {
+		t.Fatalf("expected no report after a retryable failure, got %d", len(fc.reports))
+	}
+	if attempts != 1 {
+		t.Fatalf("expected 1 attempt so far, got %d", attempts)
+	}
+
+	// Advance the clock past the backoff before the next tick is due.
+	now = now.Add(2 * time.Second)
+	if err := tk.process(context.Background(), item); err != nil {
+		t.Fatalf("process: %s", err)
+	}
+	if attempts != 2 {
+		t.Fatalf("expected 2 attempts, got %d", attempts)
+	}
+	if len(fc.reports) != 1 {
+		t.Fatalf("expected 1 report after success, got %d", len(fc.reports))
+	}
+	if fc.reports[0].Error != "" {
+		t.Fatalf("expected a successful report, got error %q", fc.reports[0].Error)
+	}
+	if _, pending := tk.pending["b1/r1"]; pending {
+		t.Fatal("expected retry state to be cleared after success")
+	}
+}
+
+func TestProcessDeadLettersOnceExhausted(t *testing.T) {
+	fc := &fakeClient{}
+	var client substratewrapper.SubstrateInstanceWrapperCommon = fc
+	now := time.Unix(0, 0)
+	d := NewDriver(&client, func(d *Driver) {
+		d.clock = func() time.Time { return now }
+	})
+	WithRetry(RetryPolicy{MaxAttempts: 2, InitialInterval: time.Millisecond})(d)
+
+	var deadLettered []string
+	WithDeadLetter(func(batchID, requestID string, _ json.RawMessage, lastErr error) {
+		deadLettered = append(deadLettered, batchID+"/"+requestID)
+	})(d)
+
+	handlerErr := errors.New("permanent failure")
+	handler := func(batchID, requestID string, message json.RawMessage) (json.RawMessage, error) {
+		return nil, handlerErr
+	}
+	tk := newTestTicker(d, handler)
+	item := polledItem{BatchID: "b1", RequestID: "r1"}
+
+	if err := tk.process(context.Background(), item); err != nil {
+		t.Fatalf("process (attempt 1): %s", err)
+	}
+	if len(deadLettered) != 0 {
+		t.Fatal("expected no dead-letter before MaxAttempts is reached")
+	}
+
+	now = now.Add(time.Second)
+	if err := tk.process(context.Background(), item); err != nil {
+		t.Fatalf("process (attempt 2): %s", err)
+	}
+	if len(deadLettered) != 1 || deadLettered[0] != "b1/r1" {
+		t.Fatalf("expected b1/r1 to be dead-lettered, got %v", deadLettered)
+	}
+	if len(fc.reports) != 1 || fc.reports[0].Error != handlerErr.Error() {
+		t.Fatalf("expected a failure report after exhausting retries, got %v", fc.reports)
+	}
+	if _, pending := tk.pending["b1/r1"]; pending {
+		t.Fatal("expected retry state to be cleared after dead-lettering")
+	}
+}
@@ -0,0 +1,415 @@
+// Command substrategen generates the net/rpc Args/Resp structs and the
+// PluginRPC/PluginRPCServer methods that forward each Substrate method over
+// net/rpc, reading the Substrate interface declaration directly out of
+// substratecommon.go via go/ast (no reflection, no protoc). It exists
+// because that boilerplate used to be hand-maintained and had drifted: a
+// copy-pasted QueryBlock forwarder called "Plugin.QueryInfo" by mistake.
+//
+// Run it with `go generate ./...` from the repo root; see the go:generate
+// directive above the Substrate interface in substratecommon.go.
+//
+// A method is left for a human to write by hand, and excluded from
+// generation entirely, if its doc comment contains one of these tags:
+//
+//   - substrate:stream   the method streams results outside of a single
+//     Args/Resp round trip (see SubscribeBlocks, which pushes values back
+//     over a MuxBroker/gRPC stream instead).
+//   - substrate:notimeout the method isn't exposed over RPC at all (see
+//     IsTimeoutError, which classifies an already-deserialized Error
+//     locally).
+//
+// Every other method must use named parameters and named results in the
+// interface declaration, since substrategen has no other source for the
+// Args/Resp struct field names.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"log"
+	"os"
+	"strings"
+)
+
+const (
+	tagStream    = "substrate:stream"
+	tagNoTimeout = "substrate:notimeout"
+)
+
+// field is a single named parameter or result.
+type field struct {
+	Name string
+	Type ast.Expr
+}
+
+// method describes one Substrate interface method, with any leading
+// context.Context parameter and trailing error result split out since both
+// are handled specially by the generated code.
+type method struct {
+	Name    string
+	HasCtx  bool
+	Params  []field
+	Results []field
+	HasErr  bool
+}
+
+// group is a base method (e.g. QueryBlock) paired with its context-aware
+// sibling (e.g. QueryBlockCtx), if the interface declares one. They share a
+// single Args/Resp struct pair.
+type group struct {
+	Base *method
+	Ctx  *method
+}
+
+func main() {
+	src := flag.String("src", "substratecommon.go", "file declaring the Substrate interface")
+	out := flag.String("out", "substrate_rpc.gen.go", "generated output file")
+	flag.Parse()
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, *src, nil, parser.ParseComments)
+	if err != nil {
+		log.Fatalf("parse %s: %v", *src, err)
+	}
+
+	iface := findInterface(f, "Substrate")
+	if iface == nil {
+		log.Fatalf("no Substrate interface found in %s", *src)
+	}
+
+	methods, err := parseMethods(iface)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src1 := generate(*src, groupMethods(methods))
+	formatted, err := format.Source(src1)
+	if err != nil {
+		os.WriteFile(*out+".raw", src1, 0644)
+		log.Fatalf("gofmt generated output: %v (unformatted output written to %s.raw)", err, *out)
+	}
+	if err := os.WriteFile(*out, formatted, 0644); err != nil {
+		log.Fatalf("write %s: %v", *out, err)
+	}
+}
+
+func findInterface(f *ast.File, name string) *ast.InterfaceType {
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts, ok := spec.(*ast.TypeSpec)
+			if !ok || ts.Name.Name != name {
+				continue
+			}
+			if it, ok := ts.Type.(*ast.InterfaceType); ok {
+				return it
+			}
+		}
+	}
+	return nil
+}
+
+// expandFields turns an *ast.FieldList, where a single ast.Field may declare
+// several names sharing one type (e.g. "tag, phylum string"), into one
+// field per name.
+func expandFields(fl *ast.FieldList) ([]field, error) {
+	if fl == nil {
+		return nil, nil
+	}
+	var out []field
+	for _, f := range fl.List {
+		if len(f.Names) == 0 {
+			return nil, fmt.Errorf("unnamed parameter/result of type %s; substrategen requires named interface methods", types.ExprString(f.Type))
+		}
+		for _, n := range f.Names {
+			out = append(out, field{Name: n.Name, Type: f.Type})
+		}
+	}
+	return out, nil
+}
+
+// expandResults is like expandFields but additionally tolerates a single
+// trailing bare "error" result with no name, which every Substrate method
+// declares instead of naming it "err error".
+func expandResults(fl *ast.FieldList) ([]field, bool, error) {
+	if fl == nil {
+		return nil, false, nil
+	}
+	list := append([]*ast.Field(nil), fl.List...)
+	hasErr := false
+	if n := len(list); n > 0 {
+		last := list[n-1]
+		if types.ExprString(last.Type) == "error" {
+			hasErr = true
+			switch len(last.Names) {
+			case 0, 1:
+				list = list[:n-1]
+			default:
+				// "(a, err error)" - keep the non-final names as regular
+				// fields, only the trailing one is the error result.
+				trimmed := *last
+				trimmed.Names = last.Names[:len(last.Names)-1]
+				list[n-1] = &trimmed
+			}
+		}
+	}
+	var out []field
+	for _, f := range list {
+		if len(f.Names) == 0 {
+			return nil, false, fmt.Errorf("unnamed result of type %s; substrategen requires named interface methods", types.ExprString(f.Type))
+		}
+		for _, n := range f.Names {
+			out = append(out, field{Name: n.Name, Type: f.Type})
+		}
+	}
+	return out, hasErr, nil
+}
+
+func parseMethods(iface *ast.InterfaceType) ([]*method, error) {
+	var methods []*method
+	for _, f := range iface.Methods.List {
+		if len(f.Names) != 1 {
+			continue // embedded interface; Substrate doesn't embed any today
+		}
+		if f.Doc != nil {
+			tags := f.Doc.Text()
+			if strings.Contains(tags, tagStream) || strings.Contains(tags, tagNoTimeout) {
+				continue
+			}
+		}
+		ft, ok := f.Type.(*ast.FuncType)
+		if !ok {
+			continue
+		}
+		m := &method{Name: f.Names[0].Name}
+		params, err := expandFields(ft.Params)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", m.Name, err)
+		}
+		if len(params) > 0 && types.ExprString(params[0].Type) == "context.Context" {
+			m.HasCtx = true
+			params = params[1:]
+		}
+		m.Params = params
+		results, hasErr, err := expandResults(ft.Results)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", m.Name, err)
+		}
+		m.HasErr = hasErr
+		m.Results = results
+		methods = append(methods, m)
+	}
+	return methods, nil
+}
+
+// groupMethods pairs each base method with its "...Ctx" sibling, if the
+// interface declares one, preserving declaration order.
+func groupMethods(methods []*method) []*group {
+	byName := make(map[string]*method, len(methods))
+	for _, m := range methods {
+		byName[m.Name] = m
+	}
+	var groups []*group
+	consumed := make(map[string]bool, len(methods))
+	for _, m := range methods {
+		if consumed[m.Name] {
+			continue
+		}
+		if strings.HasSuffix(m.Name, "Ctx") {
+			if base, ok := byName[strings.TrimSuffix(m.Name, "Ctx")]; ok && !consumed[base.Name] {
+				continue // picked up below when we reach the base method
+			}
+		}
+		g := &group{Base: m}
+		consumed[m.Name] = true
+		if ctx, ok := byName[m.Name+"Ctx"]; ok && ctx.HasCtx {
+			g.Ctx = ctx
+			consumed[ctx.Name] = true
+		}
+		groups = append(groups, g)
+	}
+	return groups
+}
+
+func exported(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// zero renders a zero value expression for t, used on early-return paths.
+func zero(t ast.Expr) string {
+	switch e := t.(type) {
+	case *ast.Ident:
+		switch e.Name {
+		case "string":
+			return `""`
+		case "bool":
+			return "false"
+		default:
+			return "0" // the remaining builtins in this interface are all numeric
+		}
+	default:
+		return "nil" // pointers, slices, maps, chans, funcs, interfaces
+	}
+}
+
+func generate(srcFile string, groups []*group) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by cmd/substrategen from the Substrate interface in %s. DO NOT EDIT.\n\n", srcFile)
+	buf.WriteString("package substratecommon\n\n")
+	buf.WriteString("import \"context\"\n")
+
+	for _, g := range groups {
+		writeArgsResp(&buf, g)
+	}
+	for _, g := range groups {
+		writePluginRPC(&buf, g)
+	}
+	for _, g := range groups {
+		writePluginRPCServer(&buf, g)
+	}
+
+	return buf.Bytes()
+}
+
+func writeArgsResp(buf *bytes.Buffer, g *group) {
+	name := g.Base.Name
+	fmt.Fprintf(buf, "\n// Args%s encodes the arguments to %s\n", name, name)
+	fmt.Fprintf(buf, "type Args%s struct {\n", name)
+	for _, p := range g.Base.Params {
+		fmt.Fprintf(buf, "\t%s %s\n", exported(p.Name), types.ExprString(p.Type))
+	}
+	buf.WriteString("}\n")
+
+	fmt.Fprintf(buf, "\n// Resp%s encodes the response from %s\n", name, name)
+	fmt.Fprintf(buf, "type Resp%s struct {\n", name)
+	for _, r := range g.Base.Results {
+		fmt.Fprintf(buf, "\t%s %s\n", exported(r.Name), types.ExprString(r.Type))
+	}
+	if g.Base.HasErr {
+		buf.WriteString("\tErr *Error\n")
+	}
+	buf.WriteString("}\n")
+}
+
+func paramList(params []field, withCtx bool) string {
+	parts := make([]string, 0, len(params)+1)
+	if withCtx {
+		parts = append(parts, "ctx context.Context")
+	}
+	for _, p := range params {
+		parts = append(parts, fmt.Sprintf("%s %s", p.Name, types.ExprString(p.Type)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func resultTypeList(results []field, hasErr bool) string {
+	parts := make([]string, 0, len(results)+1)
+	for _, r := range results {
+		parts = append(parts, types.ExprString(r.Type))
+	}
+	if hasErr {
+		parts = append(parts, "error")
+	}
+	return strings.Join(parts, ", ")
+}
+
+func zeroReturn(results []field, errExpr string) string {
+	parts := make([]string, 0, len(results)+1)
+	for _, r := range results {
+		parts = append(parts, zero(r.Type))
+	}
+	parts = append(parts, errExpr)
+	return strings.Join(parts, ", ")
+}
+
+func successReturn(results []field) string {
+	parts := make([]string, 0, len(results)+1)
+	for _, r := range results {
+		parts = append(parts, "resp."+exported(r.Name))
+	}
+	parts = append(parts, "nil")
+	return strings.Join(parts, ", ")
+}
+
+func argNames(params []field) string {
+	names := make([]string, len(params))
+	for i, p := range params {
+		names[i] = p.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+func argFieldInit(params []field) string {
+	parts := make([]string, len(params))
+	for i, p := range params {
+		parts[i] = fmt.Sprintf("%s: %s", exported(p.Name), p.Name)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func writePluginRPC(buf *bytes.Buffer, g *group) {
+	name := g.Base.Name
+	results := g.Base.Results
+	serviceMethod := "Plugin." + name
+
+	if g.Ctx == nil {
+		fmt.Fprintf(buf, "\n// %s forwards the call\n", name)
+		fmt.Fprintf(buf, "func (g *PluginRPC) %s(%s) (%s) {\n", name, paramList(g.Base.Params, false), resultTypeList(results, true))
+		fmt.Fprintf(buf, "\tvar resp Resp%s\n", name)
+		fmt.Fprintf(buf, "\terr := g.client.Call(%q, &Args%s{%s}, &resp)\n", serviceMethod, name, argFieldInit(g.Base.Params))
+		fmt.Fprintf(buf, "\tif err != nil {\n\t\treturn %s\n\t}\n", zeroReturn(results, "errRPC"))
+		buf.WriteString("\tif resp.Err != nil {\n")
+		fmt.Fprintf(buf, "\t\treturn %s\n\t}\n", zeroReturn(results, "resp.Err"))
+		fmt.Fprintf(buf, "\treturn %s\n}\n", successReturn(results))
+		return
+	}
+
+	fmt.Fprintf(buf, "\n// %s forwards the call\n", name)
+	fmt.Fprintf(buf, "func (g *PluginRPC) %s(%s) (%s) {\n", name, paramList(g.Base.Params, false), resultTypeList(results, true))
+	fmt.Fprintf(buf, "\treturn g.%sCtx(context.Background(), %s)\n}\n", name, argNames(g.Base.Params))
+
+	fmt.Fprintf(buf, "\n// %sCtx forwards the call, unblocking early if ctx is done\n", name)
+	fmt.Fprintf(buf, "func (g *PluginRPC) %sCtx(%s) (%s) {\n", name, paramList(g.Ctx.Params, true), resultTypeList(results, true))
+	fmt.Fprintf(buf, "\tvar resp Resp%s\n", name)
+	fmt.Fprintf(buf, "\terr := g.callCtx(ctx, %q, &Args%s{%s}, &resp)\n", serviceMethod, name, argFieldInit(g.Ctx.Params))
+	fmt.Fprintf(buf, "\tif err != nil {\n\t\treturn %s\n\t}\n", zeroReturn(results, "errRPC"))
+	buf.WriteString("\tif resp.Err != nil {\n")
+	fmt.Fprintf(buf, "\t\treturn %s\n\t}\n", zeroReturn(results, "resp.Err"))
+	fmt.Fprintf(buf, "\treturn %s\n}\n", successReturn(results))
+}
+
+func writePluginRPCServer(buf *bytes.Buffer, g *group) {
+	name := g.Base.Name
+	results := g.Base.Results
+
+	fmt.Fprintf(buf, "\n// %s forwards the call\n", name)
+	fmt.Fprintf(buf, "func (s *PluginRPCServer) %s(args *Args%s, resp *Resp%s) error {\n", name, name, name)
+
+	lhs := make([]string, 0, len(results)+1)
+	for _, r := range results {
+		lhs = append(lhs, r.Name)
+	}
+	lhs = append(lhs, "err")
+	callArgs := make([]string, len(g.Base.Params))
+	for i, p := range g.Base.Params {
+		callArgs[i] = "args." + exported(p.Name)
+	}
+	fmt.Fprintf(buf, "\t%s := s.Impl.%s(%s)\n", strings.Join(lhs, ", "), name, strings.Join(callArgs, ", "))
+	buf.WriteString("\tif err != nil {\n\t\tresp.Err = s.newError(err)\n\t\treturn nil\n\t}\n")
+	for _, r := range results {
+		fmt.Fprintf(buf, "\tresp.%s = %s\n", exported(r.Name), r.Name)
+	}
+	buf.WriteString("\treturn nil\n}\n")
+}
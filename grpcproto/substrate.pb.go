@@ -0,0 +1,357 @@
+// Package grpcproto contains the protobuf messages for SubstrateService,
+// generated from substrate.proto. Regenerate with:
+//
+//	protoc --go_out=. --go-grpc_out=. substrate.proto
+//
+// until cmd/substrategen takes over (see the RPC-boilerplate generator
+// request); until then this file is maintained by hand in lock-step with
+// substrate.proto.
+package grpcproto
+
+import "fmt"
+
+// Error mirrors substratecommon.Error.
+type Error struct {
+	IsTimeoutError bool   `protobuf:"varint,1,opt,name=is_timeout_error,json=isTimeoutError,proto3" json:"is_timeout_error,omitempty"`
+	Diagnostic     string `protobuf:"bytes,2,opt,name=diagnostic,proto3" json:"diagnostic,omitempty"`
+}
+
+func (m *Error) Reset()         { *m = Error{} }
+func (m *Error) String() string { return fmt.Sprintf("%+v", *m) }
+func (*Error) ProtoMessage()    {}
+
+// ConcreteRequestOptions mirrors substratecommon.ConcreteRequestOptions.
+type ConcreteRequestOptions struct {
+	Headers             map[string]string `protobuf:"bytes,1,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Endpoint            string            `protobuf:"bytes,2,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	Id                  string            `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	AuthToken           string            `protobuf:"bytes,4,opt,name=auth_token,json=authToken,proto3" json:"auth_token,omitempty"`
+	Params              []byte            `protobuf:"bytes,5,opt,name=params,proto3" json:"params,omitempty"`
+	Transient           map[string][]byte `protobuf:"bytes,6,rep,name=transient,proto3" json:"transient,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Timestamp           string            `protobuf:"bytes,7,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	MspFilter           []string          `protobuf:"bytes,8,rep,name=msp_filter,json=mspFilter,proto3" json:"msp_filter,omitempty"`
+	MinEndorsers        int32             `protobuf:"varint,9,opt,name=min_endorsers,json=minEndorsers,proto3" json:"min_endorsers,omitempty"`
+	Creator             string            `protobuf:"bytes,10,opt,name=creator,proto3" json:"creator,omitempty"`
+	DependentTxId       string            `protobuf:"bytes,11,opt,name=dependent_tx_id,json=dependentTxId,proto3" json:"dependent_tx_id,omitempty"`
+	DisableWritePolling bool              `protobuf:"varint,12,opt,name=disable_write_polling,json=disableWritePolling,proto3" json:"disable_write_polling,omitempty"`
+	CcFetchUrlDowngrade bool              `protobuf:"varint,13,opt,name=cc_fetch_url_downgrade,json=ccFetchUrlDowngrade,proto3" json:"cc_fetch_url_downgrade,omitempty"`
+	CcFetchUrlProxy     string            `protobuf:"bytes,14,opt,name=cc_fetch_url_proxy,json=ccFetchUrlProxy,proto3" json:"cc_fetch_url_proxy,omitempty"`
+}
+
+func (m *ConcreteRequestOptions) Reset()         { *m = ConcreteRequestOptions{} }
+func (m *ConcreteRequestOptions) String() string { return fmt.Sprintf("%+v", *m) }
+func (*ConcreteRequestOptions) ProtoMessage()    {}
+
+// Response mirrors substratecommon.Response.
+type Response struct {
+	ResultJson    []byte `protobuf:"bytes,1,opt,name=result_json,json=resultJson,proto3" json:"result_json,omitempty"`
+	HasError      bool   `protobuf:"varint,2,opt,name=has_error,json=hasError,proto3" json:"has_error,omitempty"`
+	ErrorCode     int32  `protobuf:"varint,3,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	ErrorMessage  string `protobuf:"bytes,4,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	ErrorJson     []byte `protobuf:"bytes,5,opt,name=error_json,json=errorJson,proto3" json:"error_json,omitempty"`
+	TransactionId string `protobuf:"bytes,6,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+}
+
+func (m *Response) Reset()         { *m = Response{} }
+func (m *Response) String() string { return fmt.Sprintf("%+v", *m) }
+func (*Response) ProtoMessage()    {}
+
+// Transaction mirrors substratecommon.Transaction.
+type Transaction struct {
+	Id          string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Reason      string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	Event       []byte `protobuf:"bytes,3,opt,name=event,proto3" json:"event,omitempty"`
+	ChaincodeId string `protobuf:"bytes,4,opt,name=chaincode_id,json=chaincodeId,proto3" json:"chaincode_id,omitempty"`
+}
+
+func (m *Transaction) Reset()         { *m = Transaction{} }
+func (m *Transaction) String() string { return fmt.Sprintf("%+v", *m) }
+func (*Transaction) ProtoMessage()    {}
+
+// Block mirrors substratecommon.Block.
+type Block struct {
+	Hash         string         `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Transactions []*Transaction `protobuf:"bytes,2,rep,name=transactions,proto3" json:"transactions,omitempty"`
+}
+
+func (m *Block) Reset()         { *m = Block{} }
+func (m *Block) String() string { return fmt.Sprintf("%+v", *m) }
+func (*Block) ProtoMessage()    {}
+
+type NewRPCRequest struct{}
+
+func (m *NewRPCRequest) Reset()         { *m = NewRPCRequest{} }
+func (m *NewRPCRequest) String() string { return fmt.Sprintf("%+v", *m) }
+func (*NewRPCRequest) ProtoMessage()    {}
+
+type NewRPCResponse struct {
+	Tag string `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	Err *Error `protobuf:"bytes,2,opt,name=err,proto3" json:"err,omitempty"`
+}
+
+func (m *NewRPCResponse) Reset()         { *m = NewRPCResponse{} }
+func (m *NewRPCResponse) String() string { return fmt.Sprintf("%+v", *m) }
+func (*NewRPCResponse) ProtoMessage()    {}
+
+type CloseRPCRequest struct {
+	Tag string `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+}
+
+func (m *CloseRPCRequest) Reset()         { *m = CloseRPCRequest{} }
+func (m *CloseRPCRequest) String() string { return fmt.Sprintf("%+v", *m) }
+func (*CloseRPCRequest) ProtoMessage()    {}
+
+type CloseRPCResponse struct {
+	Err *Error `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
+}
+
+func (m *CloseRPCResponse) Reset()         { *m = CloseRPCResponse{} }
+func (m *CloseRPCResponse) String() string { return fmt.Sprintf("%+v", *m) }
+func (*CloseRPCResponse) ProtoMessage()    {}
+
+type NewMockFromRequest struct {
+	Name     string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version  string `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	Snapshot []byte `protobuf:"bytes,3,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+}
+
+func (m *NewMockFromRequest) Reset()         { *m = NewMockFromRequest{} }
+func (m *NewMockFromRequest) String() string { return fmt.Sprintf("%+v", *m) }
+func (*NewMockFromRequest) ProtoMessage()    {}
+
+type NewMockFromResponse struct {
+	Tag string `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	Err *Error `protobuf:"bytes,2,opt,name=err,proto3" json:"err,omitempty"`
+}
+
+func (m *NewMockFromResponse) Reset()         { *m = NewMockFromResponse{} }
+func (m *NewMockFromResponse) String() string { return fmt.Sprintf("%+v", *m) }
+func (*NewMockFromResponse) ProtoMessage()    {}
+
+type SetCreatorWithAttributesMockRequest struct {
+	Tag     string            `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	Creator string            `protobuf:"bytes,2,opt,name=creator,proto3" json:"creator,omitempty"`
+	Attrs   map[string]string `protobuf:"bytes,3,rep,name=attrs,proto3" json:"attrs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (m *SetCreatorWithAttributesMockRequest) Reset()         { *m = SetCreatorWithAttributesMockRequest{} }
+func (m *SetCreatorWithAttributesMockRequest) String() string { return fmt.Sprintf("%+v", *m) }
+func (*SetCreatorWithAttributesMockRequest) ProtoMessage()    {}
+
+type SetCreatorWithAttributesMockResponse struct {
+	Err *Error `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
+}
+
+func (m *SetCreatorWithAttributesMockResponse) Reset() {
+	*m = SetCreatorWithAttributesMockResponse{}
+}
+func (m *SetCreatorWithAttributesMockResponse) String() string { return fmt.Sprintf("%+v", *m) }
+func (*SetCreatorWithAttributesMockResponse) ProtoMessage()    {}
+
+type SnapshotMockRequest struct {
+	Tag string `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+}
+
+func (m *SnapshotMockRequest) Reset()         { *m = SnapshotMockRequest{} }
+func (m *SnapshotMockRequest) String() string { return fmt.Sprintf("%+v", *m) }
+func (*SnapshotMockRequest) ProtoMessage()    {}
+
+type SnapshotMockResponse struct {
+	Snapshot []byte `protobuf:"bytes,1,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	Err      *Error `protobuf:"bytes,2,opt,name=err,proto3" json:"err,omitempty"`
+}
+
+func (m *SnapshotMockResponse) Reset()         { *m = SnapshotMockResponse{} }
+func (m *SnapshotMockResponse) String() string { return fmt.Sprintf("%+v", *m) }
+func (*SnapshotMockResponse) ProtoMessage()    {}
+
+type CloseMockRequest struct {
+	Tag string `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+}
+
+func (m *CloseMockRequest) Reset()         { *m = CloseMockRequest{} }
+func (m *CloseMockRequest) String() string { return fmt.Sprintf("%+v", *m) }
+func (*CloseMockRequest) ProtoMessage()    {}
+
+type CloseMockResponse struct {
+	Err *Error `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
+}
+
+func (m *CloseMockResponse) Reset()         { *m = CloseMockResponse{} }
+func (m *CloseMockResponse) String() string { return fmt.Sprintf("%+v", *m) }
+func (*CloseMockResponse) ProtoMessage()    {}
+
+type InitRequest struct {
+	Tag     string                  `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	Phylum  string                  `protobuf:"bytes,2,opt,name=phylum,proto3" json:"phylum,omitempty"`
+	Options *ConcreteRequestOptions `protobuf:"bytes,3,opt,name=options,proto3" json:"options,omitempty"`
+}
+
+func (m *InitRequest) Reset()         { *m = InitRequest{} }
+func (m *InitRequest) String() string { return fmt.Sprintf("%+v", *m) }
+func (*InitRequest) ProtoMessage()    {}
+
+type InitResponse struct {
+	Err *Error `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
+}
+
+func (m *InitResponse) Reset()         { *m = InitResponse{} }
+func (m *InitResponse) String() string { return fmt.Sprintf("%+v", *m) }
+func (*InitResponse) ProtoMessage()    {}
+
+type CallRequest struct {
+	Tag     string                  `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	Command string                  `protobuf:"bytes,2,opt,name=command,proto3" json:"command,omitempty"`
+	Options *ConcreteRequestOptions `protobuf:"bytes,3,opt,name=options,proto3" json:"options,omitempty"`
+}
+
+func (m *CallRequest) Reset()         { *m = CallRequest{} }
+func (m *CallRequest) String() string { return fmt.Sprintf("%+v", *m) }
+func (*CallRequest) ProtoMessage()    {}
+
+type CallResponse struct {
+	Response *Response `protobuf:"bytes,1,opt,name=response,proto3" json:"response,omitempty"`
+	Err      *Error    `protobuf:"bytes,2,opt,name=err,proto3" json:"err,omitempty"`
+}
+
+func (m *CallResponse) Reset()         { *m = CallResponse{} }
+func (m *CallResponse) String() string { return fmt.Sprintf("%+v", *m) }
+func (*CallResponse) ProtoMessage()    {}
+
+type QueryInfoRequest struct {
+	Tag     string                  `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	Options *ConcreteRequestOptions `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+}
+
+func (m *QueryInfoRequest) Reset()         { *m = QueryInfoRequest{} }
+func (m *QueryInfoRequest) String() string { return fmt.Sprintf("%+v", *m) }
+func (*QueryInfoRequest) ProtoMessage()    {}
+
+type QueryInfoResponse struct {
+	Height uint64 `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	Err    *Error `protobuf:"bytes,2,opt,name=err,proto3" json:"err,omitempty"`
+}
+
+func (m *QueryInfoResponse) Reset()         { *m = QueryInfoResponse{} }
+func (m *QueryInfoResponse) String() string { return fmt.Sprintf("%+v", *m) }
+func (*QueryInfoResponse) ProtoMessage()    {}
+
+type QueryBlockRequest struct {
+	Tag     string                  `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	Height  uint64                  `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	Options *ConcreteRequestOptions `protobuf:"bytes,3,opt,name=options,proto3" json:"options,omitempty"`
+}
+
+func (m *QueryBlockRequest) Reset()         { *m = QueryBlockRequest{} }
+func (m *QueryBlockRequest) String() string { return fmt.Sprintf("%+v", *m) }
+func (*QueryBlockRequest) ProtoMessage()    {}
+
+type QueryBlockResponse struct {
+	Block *Block `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
+	Err   *Error `protobuf:"bytes,2,opt,name=err,proto3" json:"err,omitempty"`
+}
+
+func (m *QueryBlockResponse) Reset()         { *m = QueryBlockResponse{} }
+func (m *QueryBlockResponse) String() string { return fmt.Sprintf("%+v", *m) }
+func (*QueryBlockResponse) ProtoMessage()    {}
+
+type HealthCheckRequest struct {
+	X int32 `protobuf:"varint,1,opt,name=x,proto3" json:"x,omitempty"`
+}
+
+func (m *HealthCheckRequest) Reset()         { *m = HealthCheckRequest{} }
+func (m *HealthCheckRequest) String() string { return fmt.Sprintf("%+v", *m) }
+func (*HealthCheckRequest) ProtoMessage()    {}
+
+type HealthCheckResponse struct {
+	X   int32  `protobuf:"varint,1,opt,name=x,proto3" json:"x,omitempty"`
+	Err *Error `protobuf:"bytes,2,opt,name=err,proto3" json:"err,omitempty"`
+}
+
+func (m *HealthCheckResponse) Reset()         { *m = HealthCheckResponse{} }
+func (m *HealthCheckResponse) String() string { return fmt.Sprintf("%+v", *m) }
+func (*HealthCheckResponse) ProtoMessage()    {}
+
+type SubscribeBlocksRequest struct {
+	Tag         string                  `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	StartHeight uint64                  `protobuf:"varint,2,opt,name=start_height,json=startHeight,proto3" json:"start_height,omitempty"`
+	Options     *ConcreteRequestOptions `protobuf:"bytes,3,opt,name=options,proto3" json:"options,omitempty"`
+}
+
+func (m *SubscribeBlocksRequest) Reset()         { *m = SubscribeBlocksRequest{} }
+func (m *SubscribeBlocksRequest) String() string { return fmt.Sprintf("%+v", *m) }
+func (*SubscribeBlocksRequest) ProtoMessage()    {}
+
+type SubscribeBlocksResponse struct {
+	Block *Block `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
+	Err   *Error `protobuf:"bytes,2,opt,name=err,proto3" json:"err,omitempty"`
+}
+
+func (m *SubscribeBlocksResponse) Reset()         { *m = SubscribeBlocksResponse{} }
+func (m *SubscribeBlocksResponse) String() string { return fmt.Sprintf("%+v", *m) }
+func (*SubscribeBlocksResponse) ProtoMessage()    {}
+
+type PingRequest struct{}
+
+func (m *PingRequest) Reset()         { *m = PingRequest{} }
+func (m *PingRequest) String() string { return fmt.Sprintf("%+v", *m) }
+func (*PingRequest) ProtoMessage()    {}
+
+type PingResponse struct {
+	Err *Error `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
+}
+
+func (m *PingResponse) Reset()         { *m = PingResponse{} }
+func (m *PingResponse) String() string { return fmt.Sprintf("%+v", *m) }
+func (*PingResponse) ProtoMessage()    {}
+
+type ResumeRequest struct {
+	Tags []string `protobuf:"bytes,1,rep,name=tags,proto3" json:"tags,omitempty"`
+}
+
+func (m *ResumeRequest) Reset()         { *m = ResumeRequest{} }
+func (m *ResumeRequest) String() string { return fmt.Sprintf("%+v", *m) }
+func (*ResumeRequest) ProtoMessage()    {}
+
+type ResumeResponse struct {
+	Err *Error `protobuf:"bytes,1,opt,name=err,proto3" json:"err,omitempty"`
+}
+
+func (m *ResumeResponse) Reset()         { *m = ResumeResponse{} }
+func (m *ResumeResponse) String() string { return fmt.Sprintf("%+v", *m) }
+func (*ResumeResponse) ProtoMessage()    {}
+
+// HeaderValues wraps the repeated values of one HTTP header/trailer entry,
+// since a protobuf map's value type can't itself be repeated.
+type HeaderValues struct {
+	Values []string `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+}
+
+func (m *HeaderValues) Reset()         { *m = HeaderValues{} }
+func (m *HeaderValues) String() string { return fmt.Sprintf("%+v", *m) }
+func (*HeaderValues) ProtoMessage()    {}
+
+type ServeHTTPRequest struct {
+	Tag     string                   `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	Method  string                   `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`
+	Url     string                   `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	Header  map[string]*HeaderValues `protobuf:"bytes,4,rep,name=header,proto3" json:"header,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Body    []byte                   `protobuf:"bytes,5,opt,name=body,proto3" json:"body,omitempty"`
+	Trailer map[string]*HeaderValues `protobuf:"bytes,6,rep,name=trailer,proto3" json:"trailer,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (m *ServeHTTPRequest) Reset()         { *m = ServeHTTPRequest{} }
+func (m *ServeHTTPRequest) String() string { return fmt.Sprintf("%+v", *m) }
+func (*ServeHTTPRequest) ProtoMessage()    {}
+
+type ServeHTTPResponse struct {
+	StatusCode int32                    `protobuf:"varint,1,opt,name=status_code,json=statusCode,proto3" json:"status_code,omitempty"`
+	Header     map[string]*HeaderValues `protobuf:"bytes,2,rep,name=header,proto3" json:"header,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Body       []byte                   `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	Trailer    map[string]*HeaderValues `protobuf:"bytes,4,rep,name=trailer,proto3" json:"trailer,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Err        *Error                   `protobuf:"bytes,5,opt,name=err,proto3" json:"err,omitempty"`
+}
+
+func (m *ServeHTTPResponse) Reset()         { *m = ServeHTTPResponse{} }
+func (m *ServeHTTPResponse) String() string { return fmt.Sprintf("%+v", *m) }
+func (*ServeHTTPResponse) ProtoMessage()    {}
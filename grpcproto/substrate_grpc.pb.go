@@ -0,0 +1,534 @@
+package grpcproto
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SubstrateServiceClient is the client API for SubstrateService.
+type SubstrateServiceClient interface {
+	NewRPC(ctx context.Context, in *NewRPCRequest, opts ...grpc.CallOption) (*NewRPCResponse, error)
+	CloseRPC(ctx context.Context, in *CloseRPCRequest, opts ...grpc.CallOption) (*CloseRPCResponse, error)
+
+	NewMockFrom(ctx context.Context, in *NewMockFromRequest, opts ...grpc.CallOption) (*NewMockFromResponse, error)
+	SetCreatorWithAttributesMock(ctx context.Context, in *SetCreatorWithAttributesMockRequest, opts ...grpc.CallOption) (*SetCreatorWithAttributesMockResponse, error)
+	SnapshotMock(ctx context.Context, in *SnapshotMockRequest, opts ...grpc.CallOption) (*SnapshotMockResponse, error)
+	CloseMock(ctx context.Context, in *CloseMockRequest, opts ...grpc.CallOption) (*CloseMockResponse, error)
+
+	Init(ctx context.Context, in *InitRequest, opts ...grpc.CallOption) (*InitResponse, error)
+	Call(ctx context.Context, in *CallRequest, opts ...grpc.CallOption) (*CallResponse, error)
+	QueryInfo(ctx context.Context, in *QueryInfoRequest, opts ...grpc.CallOption) (*QueryInfoResponse, error)
+	QueryBlock(ctx context.Context, in *QueryBlockRequest, opts ...grpc.CallOption) (*QueryBlockResponse, error)
+
+	HealthCheck(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) (*HealthCheckResponse, error)
+
+	SubscribeBlocks(ctx context.Context, in *SubscribeBlocksRequest, opts ...grpc.CallOption) (SubstrateService_SubscribeBlocksClient, error)
+
+	Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error)
+	Resume(ctx context.Context, in *ResumeRequest, opts ...grpc.CallOption) (*ResumeResponse, error)
+
+	ServeHTTP(ctx context.Context, in *ServeHTTPRequest, opts ...grpc.CallOption) (*ServeHTTPResponse, error)
+}
+
+type substrateServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewSubstrateServiceClient returns a SubstrateServiceClient backed by cc.
+func NewSubstrateServiceClient(cc grpc.ClientConnInterface) SubstrateServiceClient {
+	return &substrateServiceClient{cc}
+}
+
+func (c *substrateServiceClient) NewRPC(ctx context.Context, in *NewRPCRequest, opts ...grpc.CallOption) (*NewRPCResponse, error) {
+	out := new(NewRPCResponse)
+	if err := c.cc.Invoke(ctx, "/substratecommon.SubstrateService/NewRPC", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *substrateServiceClient) CloseRPC(ctx context.Context, in *CloseRPCRequest, opts ...grpc.CallOption) (*CloseRPCResponse, error) {
+	out := new(CloseRPCResponse)
+	if err := c.cc.Invoke(ctx, "/substratecommon.SubstrateService/CloseRPC", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *substrateServiceClient) NewMockFrom(ctx context.Context, in *NewMockFromRequest, opts ...grpc.CallOption) (*NewMockFromResponse, error) {
+	out := new(NewMockFromResponse)
+	if err := c.cc.Invoke(ctx, "/substratecommon.SubstrateService/NewMockFrom", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *substrateServiceClient) SetCreatorWithAttributesMock(ctx context.Context, in *SetCreatorWithAttributesMockRequest, opts ...grpc.CallOption) (*SetCreatorWithAttributesMockResponse, error) {
+	out := new(SetCreatorWithAttributesMockResponse)
+	if err := c.cc.Invoke(ctx, "/substratecommon.SubstrateService/SetCreatorWithAttributesMock", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *substrateServiceClient) SnapshotMock(ctx context.Context, in *SnapshotMockRequest, opts ...grpc.CallOption) (*SnapshotMockResponse, error) {
+	out := new(SnapshotMockResponse)
+	if err := c.cc.Invoke(ctx, "/substratecommon.SubstrateService/SnapshotMock", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *substrateServiceClient) CloseMock(ctx context.Context, in *CloseMockRequest, opts ...grpc.CallOption) (*CloseMockResponse, error) {
+	out := new(CloseMockResponse)
+	if err := c.cc.Invoke(ctx, "/substratecommon.SubstrateService/CloseMock", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *substrateServiceClient) Init(ctx context.Context, in *InitRequest, opts ...grpc.CallOption) (*InitResponse, error) {
+	out := new(InitResponse)
+	if err := c.cc.Invoke(ctx, "/substratecommon.SubstrateService/Init", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *substrateServiceClient) Call(ctx context.Context, in *CallRequest, opts ...grpc.CallOption) (*CallResponse, error) {
+	out := new(CallResponse)
+	if err := c.cc.Invoke(ctx, "/substratecommon.SubstrateService/Call", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *substrateServiceClient) QueryInfo(ctx context.Context, in *QueryInfoRequest, opts ...grpc.CallOption) (*QueryInfoResponse, error) {
+	out := new(QueryInfoResponse)
+	if err := c.cc.Invoke(ctx, "/substratecommon.SubstrateService/QueryInfo", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *substrateServiceClient) QueryBlock(ctx context.Context, in *QueryBlockRequest, opts ...grpc.CallOption) (*QueryBlockResponse, error) {
+	out := new(QueryBlockResponse)
+	if err := c.cc.Invoke(ctx, "/substratecommon.SubstrateService/QueryBlock", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *substrateServiceClient) HealthCheck(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) (*HealthCheckResponse, error) {
+	out := new(HealthCheckResponse)
+	if err := c.cc.Invoke(ctx, "/substratecommon.SubstrateService/HealthCheck", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *substrateServiceClient) SubscribeBlocks(ctx context.Context, in *SubscribeBlocksRequest, opts ...grpc.CallOption) (SubstrateService_SubscribeBlocksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_SubstrateService_serviceDesc.Streams[0], "/substratecommon.SubstrateService/SubscribeBlocks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &substrateServiceSubscribeBlocksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// SubstrateService_SubscribeBlocksClient is the client-side stream handle
+// for the SubscribeBlocks server-streaming RPC.
+type SubstrateService_SubscribeBlocksClient interface {
+	Recv() (*SubscribeBlocksResponse, error)
+	grpc.ClientStream
+}
+
+type substrateServiceSubscribeBlocksClient struct {
+	grpc.ClientStream
+}
+
+func (x *substrateServiceSubscribeBlocksClient) Recv() (*SubscribeBlocksResponse, error) {
+	m := new(SubscribeBlocksResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *substrateServiceClient) Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error) {
+	out := new(PingResponse)
+	if err := c.cc.Invoke(ctx, "/substratecommon.SubstrateService/Ping", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *substrateServiceClient) Resume(ctx context.Context, in *ResumeRequest, opts ...grpc.CallOption) (*ResumeResponse, error) {
+	out := new(ResumeResponse)
+	if err := c.cc.Invoke(ctx, "/substratecommon.SubstrateService/Resume", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *substrateServiceClient) ServeHTTP(ctx context.Context, in *ServeHTTPRequest, opts ...grpc.CallOption) (*ServeHTTPResponse, error) {
+	out := new(ServeHTTPResponse)
+	if err := c.cc.Invoke(ctx, "/substratecommon.SubstrateService/ServeHTTP", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SubstrateServiceServer is the server API for SubstrateService.
+type SubstrateServiceServer interface {
+	NewRPC(context.Context, *NewRPCRequest) (*NewRPCResponse, error)
+	CloseRPC(context.Context, *CloseRPCRequest) (*CloseRPCResponse, error)
+
+	NewMockFrom(context.Context, *NewMockFromRequest) (*NewMockFromResponse, error)
+	SetCreatorWithAttributesMock(context.Context, *SetCreatorWithAttributesMockRequest) (*SetCreatorWithAttributesMockResponse, error)
+	SnapshotMock(context.Context, *SnapshotMockRequest) (*SnapshotMockResponse, error)
+	CloseMock(context.Context, *CloseMockRequest) (*CloseMockResponse, error)
+
+	Init(context.Context, *InitRequest) (*InitResponse, error)
+	Call(context.Context, *CallRequest) (*CallResponse, error)
+	QueryInfo(context.Context, *QueryInfoRequest) (*QueryInfoResponse, error)
+	QueryBlock(context.Context, *QueryBlockRequest) (*QueryBlockResponse, error)
+
+	HealthCheck(context.Context, *HealthCheckRequest) (*HealthCheckResponse, error)
+
+	SubscribeBlocks(*SubscribeBlocksRequest, SubstrateService_SubscribeBlocksServer) error
+
+	Ping(context.Context, *PingRequest) (*PingResponse, error)
+	Resume(context.Context, *ResumeRequest) (*ResumeResponse, error)
+
+	ServeHTTP(context.Context, *ServeHTTPRequest) (*ServeHTTPResponse, error)
+}
+
+// SubstrateService_SubscribeBlocksServer is the server-side stream handle
+// for the SubscribeBlocks server-streaming RPC.
+type SubstrateService_SubscribeBlocksServer interface {
+	Send(*SubscribeBlocksResponse) error
+	grpc.ServerStream
+}
+
+type substrateServiceSubscribeBlocksServer struct {
+	grpc.ServerStream
+}
+
+func (x *substrateServiceSubscribeBlocksServer) Send(m *SubscribeBlocksResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// UnimplementedSubstrateServiceServer can be embedded to have forward
+// compatible implementations.
+type UnimplementedSubstrateServiceServer struct{}
+
+func (*UnimplementedSubstrateServiceServer) NewRPC(context.Context, *NewRPCRequest) (*NewRPCResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NewRPC not implemented")
+}
+func (*UnimplementedSubstrateServiceServer) CloseRPC(context.Context, *CloseRPCRequest) (*CloseRPCResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloseRPC not implemented")
+}
+func (*UnimplementedSubstrateServiceServer) NewMockFrom(context.Context, *NewMockFromRequest) (*NewMockFromResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NewMockFrom not implemented")
+}
+func (*UnimplementedSubstrateServiceServer) SetCreatorWithAttributesMock(context.Context, *SetCreatorWithAttributesMockRequest) (*SetCreatorWithAttributesMockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetCreatorWithAttributesMock not implemented")
+}
+func (*UnimplementedSubstrateServiceServer) SnapshotMock(context.Context, *SnapshotMockRequest) (*SnapshotMockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SnapshotMock not implemented")
+}
+func (*UnimplementedSubstrateServiceServer) CloseMock(context.Context, *CloseMockRequest) (*CloseMockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloseMock not implemented")
+}
+func (*UnimplementedSubstrateServiceServer) Init(context.Context, *InitRequest) (*InitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Init not implemented")
+}
+func (*UnimplementedSubstrateServiceServer) Call(context.Context, *CallRequest) (*CallResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Call not implemented")
+}
+func (*UnimplementedSubstrateServiceServer) QueryInfo(context.Context, *QueryInfoRequest) (*QueryInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryInfo not implemented")
+}
+func (*UnimplementedSubstrateServiceServer) QueryBlock(context.Context, *QueryBlockRequest) (*QueryBlockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryBlock not implemented")
+}
+func (*UnimplementedSubstrateServiceServer) HealthCheck(context.Context, *HealthCheckRequest) (*HealthCheckResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HealthCheck not implemented")
+}
+func (*UnimplementedSubstrateServiceServer) SubscribeBlocks(*SubscribeBlocksRequest, SubstrateService_SubscribeBlocksServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeBlocks not implemented")
+}
+func (*UnimplementedSubstrateServiceServer) Ping(context.Context, *PingRequest) (*PingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Ping not implemented")
+}
+func (*UnimplementedSubstrateServiceServer) Resume(context.Context, *ResumeRequest) (*ResumeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Resume not implemented")
+}
+func (*UnimplementedSubstrateServiceServer) ServeHTTP(context.Context, *ServeHTTPRequest) (*ServeHTTPResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ServeHTTP not implemented")
+}
+
+// RegisterSubstrateServiceServer registers srv with s.
+func RegisterSubstrateServiceServer(s *grpc.Server, srv SubstrateServiceServer) {
+	s.RegisterService(&_SubstrateService_serviceDesc, srv)
+}
+
+func _SubstrateService_NewRPC_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NewRPCRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubstrateServiceServer).NewRPC(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/substratecommon.SubstrateService/NewRPC"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubstrateServiceServer).NewRPC(ctx, req.(*NewRPCRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubstrateService_CloseRPC_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloseRPCRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubstrateServiceServer).CloseRPC(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/substratecommon.SubstrateService/CloseRPC"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubstrateServiceServer).CloseRPC(ctx, req.(*CloseRPCRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubstrateService_NewMockFrom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NewMockFromRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubstrateServiceServer).NewMockFrom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/substratecommon.SubstrateService/NewMockFrom"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubstrateServiceServer).NewMockFrom(ctx, req.(*NewMockFromRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubstrateService_SetCreatorWithAttributesMock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetCreatorWithAttributesMockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubstrateServiceServer).SetCreatorWithAttributesMock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/substratecommon.SubstrateService/SetCreatorWithAttributesMock"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubstrateServiceServer).SetCreatorWithAttributesMock(ctx, req.(*SetCreatorWithAttributesMockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubstrateService_SnapshotMock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SnapshotMockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubstrateServiceServer).SnapshotMock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/substratecommon.SubstrateService/SnapshotMock"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubstrateServiceServer).SnapshotMock(ctx, req.(*SnapshotMockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubstrateService_CloseMock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloseMockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubstrateServiceServer).CloseMock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/substratecommon.SubstrateService/CloseMock"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubstrateServiceServer).CloseMock(ctx, req.(*CloseMockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubstrateService_Init_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubstrateServiceServer).Init(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/substratecommon.SubstrateService/Init"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubstrateServiceServer).Init(ctx, req.(*InitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubstrateService_Call_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CallRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubstrateServiceServer).Call(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/substratecommon.SubstrateService/Call"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubstrateServiceServer).Call(ctx, req.(*CallRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubstrateService_QueryInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubstrateServiceServer).QueryInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/substratecommon.SubstrateService/QueryInfo"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubstrateServiceServer).QueryInfo(ctx, req.(*QueryInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubstrateService_QueryBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubstrateServiceServer).QueryBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/substratecommon.SubstrateService/QueryBlock"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubstrateServiceServer).QueryBlock(ctx, req.(*QueryBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubstrateService_HealthCheck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HealthCheckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubstrateServiceServer).HealthCheck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/substratecommon.SubstrateService/HealthCheck"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubstrateServiceServer).HealthCheck(ctx, req.(*HealthCheckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubstrateService_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubstrateServiceServer).Ping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/substratecommon.SubstrateService/Ping"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubstrateServiceServer).Ping(ctx, req.(*PingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubstrateService_Resume_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubstrateServiceServer).Resume(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/substratecommon.SubstrateService/Resume"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubstrateServiceServer).Resume(ctx, req.(*ResumeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubstrateService_ServeHTTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ServeHTTPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubstrateServiceServer).ServeHTTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/substratecommon.SubstrateService/ServeHTTP"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubstrateServiceServer).ServeHTTP(ctx, req.(*ServeHTTPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubstrateService_SubscribeBlocks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeBlocksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SubstrateServiceServer).SubscribeBlocks(m, &substrateServiceSubscribeBlocksServer{stream})
+}
+
+var _SubstrateService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "substratecommon.SubstrateService",
+	HandlerType: (*SubstrateServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "NewRPC", Handler: _SubstrateService_NewRPC_Handler},
+		{MethodName: "CloseRPC", Handler: _SubstrateService_CloseRPC_Handler},
+		{MethodName: "NewMockFrom", Handler: _SubstrateService_NewMockFrom_Handler},
+		{MethodName: "SetCreatorWithAttributesMock", Handler: _SubstrateService_SetCreatorWithAttributesMock_Handler},
+		{MethodName: "SnapshotMock", Handler: _SubstrateService_SnapshotMock_Handler},
+		{MethodName: "CloseMock", Handler: _SubstrateService_CloseMock_Handler},
+		{MethodName: "Init", Handler: _SubstrateService_Init_Handler},
+		{MethodName: "Call", Handler: _SubstrateService_Call_Handler},
+		{MethodName: "QueryInfo", Handler: _SubstrateService_QueryInfo_Handler},
+		{MethodName: "QueryBlock", Handler: _SubstrateService_QueryBlock_Handler},
+		{MethodName: "HealthCheck", Handler: _SubstrateService_HealthCheck_Handler},
+		{MethodName: "Ping", Handler: _SubstrateService_Ping_Handler},
+		{MethodName: "Resume", Handler: _SubstrateService_Resume_Handler},
+		{MethodName: "ServeHTTP", Handler: _SubstrateService_ServeHTTP_Handler},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeBlocks",
+			Handler:       _SubstrateService_SubscribeBlocks_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "substrate.proto",
+}
+
+var _ proto.Message = (*Error)(nil)
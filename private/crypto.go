@@ -0,0 +1,294 @@
+package private
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// Encryptor seals and opens a private payload under a symmetric key.
+// Built-in algorithms are registered under a name by RegisterEncryptor;
+// a TransformHeader references one by that name, so the envelope it
+// produces self-describes which Encryptor to reverse it with and new
+// algorithms can be added without changing TransformHeader's schema.
+type Encryptor interface {
+	// Seal encrypts plaintext under key and nonce, authenticating aad
+	// alongside it without including it in the output.
+	Seal(key, nonce, plaintext, aad []byte) ([]byte, error)
+	// Open reverses Seal.
+	Open(key, nonce, ciphertext, aad []byte) ([]byte, error)
+	// NonceSize is the length of the nonce Seal/Open expect.
+	NonceSize() int
+	// KeySize is the length of the symmetric key Seal/Open expect. An
+	// Encryptor with a KeySize of 0 doesn't use a key at all, e.g.
+	// EncryptorNone.
+	KeySize() int
+}
+
+// Compressor compresses a private payload before it's sealed, and
+// reverses that after Open. Built-in algorithms are registered under a
+// name by RegisterCompressor.
+type Compressor interface {
+	Compress(data []byte) ([]byte, error)
+	Decompress(data []byte) ([]byte, error)
+}
+
+// Supported built-in Encryptor names. EncryptorNone leaves the payload in
+// the clear, which is only useful when a Transform is solely extracting a
+// Profile and doesn't need confidentiality.
+const (
+	EncryptorNone             = ""
+	EncryptorAES256           = "aes256"
+	EncryptorChaCha20Poly1305 = "chacha20poly1305"
+)
+
+// Supported built-in Compressor names.
+const (
+	CompressorNone = ""
+	CompressorZlib = "zlib"
+	CompressorGzip = "gzip"
+	CompressorZstd = "zstd"
+)
+
+var (
+	encryptorsMu sync.RWMutex
+	encryptors   = map[string]Encryptor{}
+
+	compressorsMu sync.RWMutex
+	compressors   = map[string]Compressor{}
+)
+
+func init() {
+	RegisterEncryptor(EncryptorNone, noneEncryptor{})
+	RegisterEncryptor(EncryptorAES256, aesGCMEncryptor{})
+	RegisterEncryptor(EncryptorChaCha20Poly1305, chaCha20Poly1305Encryptor{})
+
+	RegisterCompressor(CompressorNone, noneCompressor{})
+	RegisterCompressor(CompressorZlib, zlibCompressor{})
+	RegisterCompressor(CompressorGzip, gzipCompressor{})
+	RegisterCompressor(CompressorZstd, zstdCompressor{})
+}
+
+// RegisterEncryptor makes e available under name for a TransformHeader's
+// Encryptor field to reference. Registering under an already-registered
+// name replaces it; this is how a deployment swaps in a stronger cipher
+// suite without a breaking change to TransformHeader.
+func RegisterEncryptor(name string, e Encryptor) {
+	encryptorsMu.Lock()
+	defer encryptorsMu.Unlock()
+	encryptors[name] = e
+}
+
+func lookupEncryptor(name string) (Encryptor, bool) {
+	encryptorsMu.RLock()
+	defer encryptorsMu.RUnlock()
+	e, ok := encryptors[name]
+	return e, ok
+}
+
+// RegisterCompressor makes c available under name for a TransformHeader's
+// Compressor field to reference. Registering under an already-registered
+// name replaces it.
+func RegisterCompressor(name string, c Compressor) {
+	compressorsMu.Lock()
+	defer compressorsMu.Unlock()
+	compressors[name] = c
+}
+
+func lookupCompressor(name string) (Compressor, bool) {
+	compressorsMu.RLock()
+	defer compressorsMu.RUnlock()
+	c, ok := compressors[name]
+	return c, ok
+}
+
+// noneEncryptor is EncryptorNone: it leaves the payload untouched.
+type noneEncryptor struct{}
+
+func (noneEncryptor) Seal(_, _, plaintext, _ []byte) ([]byte, error)  { return plaintext, nil }
+func (noneEncryptor) Open(_, _, ciphertext, _ []byte) ([]byte, error) { return ciphertext, nil }
+func (noneEncryptor) NonceSize() int                                  { return 0 }
+func (noneEncryptor) KeySize() int                                    { return 0 }
+
+// aesGCMEncryptor is EncryptorAES256.
+type aesGCMEncryptor struct{}
+
+func (aesGCMEncryptor) gcm(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("private: aes256 cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+func (e aesGCMEncryptor) Seal(key, nonce, plaintext, aad []byte) ([]byte, error) {
+	gcm, err := e.gcm(key)
+	if err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nil, nonce, plaintext, aad), nil
+}
+
+func (e aesGCMEncryptor) Open(key, nonce, ciphertext, aad []byte) ([]byte, error) {
+	gcm, err := e.gcm(key)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, aad)
+	if err != nil {
+		return nil, fmt.Errorf("private: aes256 decrypt: %w", err)
+	}
+	return plaintext, nil
+}
+
+func (aesGCMEncryptor) NonceSize() int { return 12 }
+func (aesGCMEncryptor) KeySize() int   { return 32 }
+
+// chaCha20Poly1305Encryptor is EncryptorChaCha20Poly1305.
+type chaCha20Poly1305Encryptor struct{}
+
+func (chaCha20Poly1305Encryptor) Seal(key, nonce, plaintext, aad []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, fmt.Errorf("private: chacha20poly1305 cipher: %w", err)
+	}
+	return aead.Seal(nil, nonce, plaintext, aad), nil
+}
+
+func (chaCha20Poly1305Encryptor) Open(key, nonce, ciphertext, aad []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, fmt.Errorf("private: chacha20poly1305 cipher: %w", err)
+	}
+	plaintext, err := aead.Open(nil, nonce, ciphertext, aad)
+	if err != nil {
+		return nil, fmt.Errorf("private: chacha20poly1305 decrypt: %w", err)
+	}
+	return plaintext, nil
+}
+
+func (chaCha20Poly1305Encryptor) NonceSize() int { return chacha20poly1305.NonceSize }
+func (chaCha20Poly1305Encryptor) KeySize() int   { return chacha20poly1305.KeySize }
+
+// noneCompressor is CompressorNone: it leaves the payload untouched.
+type noneCompressor struct{}
+
+func (noneCompressor) Compress(data []byte) ([]byte, error)   { return data, nil }
+func (noneCompressor) Decompress(data []byte) ([]byte, error) { return data, nil }
+
+// zlibCompressor is CompressorZlib.
+type zlibCompressor struct{}
+
+func (zlibCompressor) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, fmt.Errorf("private: zlib compress: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("private: zlib compress: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (zlibCompressor) Decompress(data []byte) ([]byte, error) {
+	r, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("private: zlib decompress: %w", err)
+	}
+	defer r.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("private: zlib decompress: %w", err)
+	}
+	return out, nil
+}
+
+// gzipCompressor is CompressorGzip.
+type gzipCompressor struct{}
+
+func (gzipCompressor) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, fmt.Errorf("private: gzip compress: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("private: gzip compress: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (gzipCompressor) Decompress(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("private: gzip decompress: %w", err)
+	}
+	defer r.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("private: gzip decompress: %w", err)
+	}
+	return out, nil
+}
+
+// zstdCompressor is CompressorZstd.
+type zstdCompressor struct{}
+
+func (zstdCompressor) Compress(data []byte) ([]byte, error) {
+	enc, err := zstd.NewWriter(nil)
+	if err != nil {
+		return nil, fmt.Errorf("private: zstd compress: %w", err)
+	}
+	defer enc.Close()
+	return enc.EncodeAll(data, nil), nil
+}
+
+func (zstdCompressor) Decompress(data []byte) ([]byte, error) {
+	dec, err := zstd.NewReader(nil)
+	if err != nil {
+		return nil, fmt.Errorf("private: zstd decompress: %w", err)
+	}
+	defer dec.Close()
+	out, err := dec.DecodeAll(data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("private: zstd decompress: %w", err)
+	}
+	return out, nil
+}
+
+// symmetricKey reads the base64-encoded symmetric key an Encryptor named
+// encryptorName uses, from an env var derived from its name (e.g.
+// EncryptorAES256's key is PRIVATE_AES256_KEY). This is a placeholder key
+// source - a single static key per algorithm - until encryptors are
+// backed by a proper key-management service.
+func symmetricKey(encryptorName string, size int) ([]byte, error) {
+	envName := symmetricKeyEnv(encryptorName)
+	encoded := os.Getenv(envName)
+	if encoded == "" {
+		return nil, fmt.Errorf("private: %s is not set; encryptor %q needs a %d-byte base64 key", envName, encryptorName, size)
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("private: decoding %s: %w", envName, err)
+	}
+	if len(key) != size {
+		return nil, fmt.Errorf("private: %s must decode to %d bytes, got %d", envName, size, len(key))
+	}
+	return key, nil
+}
+
+func symmetricKeyEnv(encryptorName string) string {
+	return "PRIVATE_" + strings.ToUpper(encryptorName) + "_KEY"
+}
@@ -0,0 +1,96 @@
+package private_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/luthersystems/substratecommon/private"
+)
+
+// testMessage round-trips through Encode/Decode below. Encode/Decode
+// don't use the client argument, so these tests exercise the pure
+// compress/encrypt/decrypt logic without needing a connected phylum.
+type testMessage struct {
+	Hello string `json:"hello"`
+	Fnord string `json:"fnord"`
+}
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	kp, err := private.NewLocalKeyProvider("test-key", make([]byte, 32))
+	if err != nil {
+		t.Fatalf("NewLocalKeyProvider: %s", err)
+	}
+
+	encryptors := []string{private.EncryptorNone, private.EncryptorAES256, private.EncryptorChaCha20Poly1305}
+	compressors := []string{private.CompressorNone, private.CompressorZlib, private.CompressorGzip, private.CompressorZstd}
+
+	for _, encryptor := range encryptors {
+		for _, compressor := range compressors {
+			name := encryptor + "/" + compressor
+			if encryptor == private.EncryptorNone {
+				name = "none/" + compressor
+			}
+			t.Run(name, func(t *testing.T) {
+				message := testMessage{Hello: "world", Fnord: "fnord"}
+				transforms := []*private.Transform{
+					{
+						ContextPath: ".",
+						Header: &private.TransformHeader{
+							ProfilePaths: []string{".fnord"},
+							Encryptor:    encryptor,
+							Compressor:   compressor,
+						},
+					},
+				}
+
+				encoded, err := private.Encode(context.Background(), nil, message, transforms, private.WithKeyProvider(kp))
+				if err != nil {
+					t.Fatalf("encode: %s", err)
+				}
+
+				var decoded testMessage
+				if err := private.Decode(context.Background(), nil, encoded, &decoded, private.WithKeyProvider(kp)); err != nil {
+					t.Fatalf("decode: %s", err)
+				}
+				if decoded != message {
+					t.Fatalf("message mismatch, expected: %v != got: %v", message, decoded)
+				}
+			})
+		}
+	}
+}
+
+func TestDecodeWithWrongKeyProviderFails(t *testing.T) {
+	kp, err := private.NewLocalKeyProvider("test-key", make([]byte, 32))
+	if err != nil {
+		t.Fatalf("NewLocalKeyProvider: %s", err)
+	}
+	otherKey := make([]byte, 32)
+	otherKey[0] = 1
+	wrongKP, err := private.NewLocalKeyProvider("test-key", otherKey)
+	if err != nil {
+		t.Fatalf("NewLocalKeyProvider: %s", err)
+	}
+
+	message := testMessage{Hello: "world", Fnord: "fnord"}
+	transforms := []*private.Transform{
+		{
+			ContextPath: ".",
+			Header: &private.TransformHeader{
+				ProfilePaths: []string{".fnord"},
+				Encryptor:    private.EncryptorAES256,
+				Compressor:   private.CompressorZlib,
+			},
+		},
+	}
+
+	encoded, err := private.Encode(context.Background(), nil, message, transforms, private.WithKeyProvider(kp))
+	if err != nil {
+		t.Fatalf("encode: %s", err)
+	}
+
+	var decoded testMessage
+	if err := private.Decode(context.Background(), nil, encoded, &decoded, private.WithKeyProvider(wrongKP)); err == nil {
+		t.Fatal("expected decode with the wrong key provider to fail")
+	}
+}
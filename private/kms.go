@@ -0,0 +1,172 @@
+package private
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"sync"
+)
+
+// KeyProvider wraps and unwraps a per-transform data-encryption key (DEK)
+// with an externally managed key, so the DEK protecting a Transform's
+// payload never needs to be derived from, or stored as, a static local
+// secret. Built-in providers are registered under a name by
+// RegisterKeyProvider; a TransformHeader references one by that name in
+// its KeyProvider field.
+type KeyProvider interface {
+	// WrapDEK encrypts dek under the provider's key-encryption key,
+	// returning the wrapped DEK and a reference to the key used to wrap
+	// it. Both are stored on the envelope for UnwrapDEK to later recover
+	// dek.
+	WrapDEK(ctx context.Context, dek []byte) (wrapped []byte, keyRef string, err error)
+	// UnwrapDEK reverses WrapDEK.
+	UnwrapDEK(ctx context.Context, wrapped []byte, keyRef string) ([]byte, error)
+}
+
+var (
+	keyProvidersMu sync.RWMutex
+	keyProviders   = map[string]KeyProvider{}
+)
+
+// RegisterKeyProvider makes kp available under name for a
+// TransformHeader's KeyProvider field to reference. Registering under an
+// already-registered name replaces it.
+func RegisterKeyProvider(name string, kp KeyProvider) {
+	keyProvidersMu.Lock()
+	defer keyProvidersMu.Unlock()
+	keyProviders[name] = kp
+}
+
+func lookupKeyProvider(name string) (KeyProvider, bool) {
+	keyProvidersMu.RLock()
+	defer keyProvidersMu.RUnlock()
+	kp, ok := keyProviders[name]
+	return kp, ok
+}
+
+// LocalKeyProvider is a KeyProvider backed by a single static key held in
+// memory. It's the simplest provider, useful for development and tests;
+// production deployments should prefer AWSKeyProvider/GCPKeyProvider.
+type LocalKeyProvider struct {
+	keyRef    string
+	masterKey []byte
+}
+
+// NewLocalKeyProvider returns a LocalKeyProvider that wraps DEKs with
+// masterKey (a 32-byte AES-256-GCM key) under keyRef.
+func NewLocalKeyProvider(keyRef string, masterKey []byte) (*LocalKeyProvider, error) {
+	var enc aesGCMEncryptor
+	if len(masterKey) != enc.KeySize() {
+		return nil, fmt.Errorf("private: local key provider needs a %d-byte master key, got %d", enc.KeySize(), len(masterKey))
+	}
+	return &LocalKeyProvider{keyRef: keyRef, masterKey: masterKey}, nil
+}
+
+// WrapDEK implements KeyProvider.
+func (p *LocalKeyProvider) WrapDEK(_ context.Context, dek []byte) ([]byte, string, error) {
+	var enc aesGCMEncryptor
+	nonce := make([]byte, enc.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, "", fmt.Errorf("private: local key provider nonce: %w", err)
+	}
+	sealed, err := enc.Seal(p.masterKey, nonce, dek, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	return append(nonce, sealed...), p.keyRef, nil
+}
+
+// UnwrapDEK implements KeyProvider.
+func (p *LocalKeyProvider) UnwrapDEK(_ context.Context, wrapped []byte, keyRef string) ([]byte, error) {
+	if keyRef != p.keyRef {
+		return nil, fmt.Errorf("private: local key provider: unknown key ref %q", keyRef)
+	}
+	var enc aesGCMEncryptor
+	if len(wrapped) < enc.NonceSize() {
+		return nil, fmt.Errorf("private: local key provider: wrapped key is shorter than a nonce")
+	}
+	nonce, sealed := wrapped[:enc.NonceSize()], wrapped[enc.NonceSize():]
+	return enc.Open(p.masterKey, nonce, sealed, nil)
+}
+
+// AWSKMSClient is the subset of an AWS KMS client AWSKeyProvider needs.
+// It's satisfied by a thin adapter the caller writes over
+// github.com/aws/aws-sdk-go-v2/service/kms's Client, so this package
+// doesn't need the AWS SDK as a dependency.
+type AWSKMSClient interface {
+	Encrypt(ctx context.Context, keyID string, plaintext []byte) (ciphertext []byte, err error)
+	Decrypt(ctx context.Context, ciphertext []byte) (plaintext []byte, err error)
+}
+
+// AWSKeyProvider is a KeyProvider backed by AWS KMS.
+type AWSKeyProvider struct {
+	client AWSKMSClient
+	keyID  string
+}
+
+// NewAWSKeyProvider returns an AWSKeyProvider that wraps DEKs under keyID
+// via client.
+func NewAWSKeyProvider(client AWSKMSClient, keyID string) *AWSKeyProvider {
+	return &AWSKeyProvider{client: client, keyID: keyID}
+}
+
+// WrapDEK implements KeyProvider.
+func (p *AWSKeyProvider) WrapDEK(ctx context.Context, dek []byte) ([]byte, string, error) {
+	wrapped, err := p.client.Encrypt(ctx, p.keyID, dek)
+	if err != nil {
+		return nil, "", fmt.Errorf("private: aws kms wrap: %w", err)
+	}
+	return wrapped, p.keyID, nil
+}
+
+// UnwrapDEK implements KeyProvider. AWS KMS ciphertexts are
+// self-describing, so keyRef isn't needed to unwrap them; it's only
+// carried for audit/rotation visibility.
+func (p *AWSKeyProvider) UnwrapDEK(ctx context.Context, wrapped []byte, _ string) ([]byte, error) {
+	dek, err := p.client.Decrypt(ctx, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("private: aws kms unwrap: %w", err)
+	}
+	return dek, nil
+}
+
+// GCPKMSClient is the subset of a GCP Cloud KMS client GCPKeyProvider
+// needs. It's satisfied by a thin adapter the caller writes over
+// cloud.google.com/go/kms/apiv1's Client, so this package doesn't need
+// the GCP SDK as a dependency.
+type GCPKMSClient interface {
+	Encrypt(ctx context.Context, keyName string, plaintext []byte) (ciphertext []byte, err error)
+	Decrypt(ctx context.Context, keyName string, ciphertext []byte) (plaintext []byte, err error)
+}
+
+// GCPKeyProvider is a KeyProvider backed by GCP Cloud KMS.
+type GCPKeyProvider struct {
+	client  GCPKMSClient
+	keyName string
+}
+
+// NewGCPKeyProvider returns a GCPKeyProvider that wraps DEKs under
+// keyName (a full Cloud KMS CryptoKey resource name) via client.
+func NewGCPKeyProvider(client GCPKMSClient, keyName string) *GCPKeyProvider {
+	return &GCPKeyProvider{client: client, keyName: keyName}
+}
+
+// WrapDEK implements KeyProvider.
+func (p *GCPKeyProvider) WrapDEK(ctx context.Context, dek []byte) ([]byte, string, error) {
+	wrapped, err := p.client.Encrypt(ctx, p.keyName, dek)
+	if err != nil {
+		return nil, "", fmt.Errorf("private: gcp kms wrap: %w", err)
+	}
+	return wrapped, p.keyName, nil
+}
+
+// UnwrapDEK implements KeyProvider. keyRef is the CryptoKey resource name
+// WrapDEK recorded, which Decrypt needs since GCP Cloud KMS ciphertexts
+// aren't self-describing the way AWS KMS's are.
+func (p *GCPKeyProvider) UnwrapDEK(ctx context.Context, wrapped []byte, keyRef string) ([]byte, error) {
+	dek, err := p.client.Decrypt(ctx, keyRef, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("private: gcp kms unwrap: %w", err)
+	}
+	return dek, nil
+}
@@ -0,0 +1,534 @@
+// Package private gives phyla a client-side story for handling data that
+// must be kept off the public ledger: encoding a message so only its
+// declared "private" fields are encrypted (while a "profile" projection
+// stays in the clear for indexing), and mapping that profile back to a
+// durable data-subject ID so it can later be exported or purged.
+package private
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/luthersystems/substratecommon"
+	"github.com/luthersystems/substratecommon/substratewrapper"
+)
+
+// Codec selects the wire format Encode/EncodeProto marshal a message with
+// before a Transform is applied. It is recorded in the envelope so
+// Decode/DecodeProto can reverse either format without the caller having
+// to remember which one was used.
+type Codec string
+
+// Supported Codec values. CodecJSON is the default.
+const (
+	CodecJSON  Codec = ""
+	CodecProto Codec = "proto"
+)
+
+// TransformHeader describes how a Transform splits a message into a
+// public Profile (kept in the clear, for indexing and ProfileToDSID) and
+// a private payload (encrypted and compressed per Encryptor/Compressor).
+// The entire message is sealed as a unit; there is currently no way to
+// encrypt only part of it, so ProfilePaths is purely additive: it copies
+// fields out into the clear-text Profile alongside the fully sealed
+// payload rather than carving them out of it. ProfilePaths entries are
+// dotted field paths rooted at the Transform's ContextPath, e.g. "." for
+// the whole document or ".fnord" for a top-level field. Encryptor and
+// Compressor name algorithms registered with
+// RegisterEncryptor/RegisterCompressor (e.g. EncryptorAES256,
+// CompressorZlib) rather than a closed, hardcoded set, so the envelope
+// self-describes how to reverse it and old payloads keep decoding as new
+// algorithms are added.
+//
+// KeyProvider names a KeyProvider registered with RegisterKeyProvider
+// (e.g. a KMS) that wraps the random data-encryption key generated for
+// this Transform, instead of Encryptor using a static local key. It's
+// left empty to keep using a static local key.
+type TransformHeader struct {
+	ProfilePaths []string
+	Encryptor    string
+	Compressor   string
+	Codec        Codec
+	KeyProvider  string
+}
+
+// Transform applies a TransformHeader at ContextPath within a message.
+// Only the root context path ("." or "") is currently supported.
+type Transform struct {
+	ContextPath string
+	Header      *TransformHeader
+}
+
+// mxfVersion marks an encoded payload as a private message-transform
+// format (MXF) envelope, as opposed to a plain, untransformed message.
+// Decode falls back to treating anything that doesn't parse as a current
+// envelope as a plain passthrough message.
+const mxfVersion = 1
+
+type mxfEnvelope struct {
+	V           int             `json:"v"`
+	Codec       Codec           `json:"codec,omitempty"`
+	Encryptor   string          `json:"encryptor,omitempty"`
+	Compressor  string          `json:"compressor,omitempty"`
+	Profile     json.RawMessage `json:"profile,omitempty"`
+	Ciphertext  []byte          `json:"ciphertext,omitempty"`
+	KeyProvider string          `json:"key_provider,omitempty"`
+	KeyRef      string          `json:"key_ref,omitempty"`
+	WrappedKey  []byte          `json:"wrapped_key,omitempty"`
+}
+
+const (
+	exportEndpoint        = "private_export"
+	purgeEndpoint         = "private_purge"
+	profileToDSIDEndpoint = "private_profile_to_dsid"
+)
+
+// Export retrieves the private data previously filed under dsid and
+// unmarshals it into out. It errors if dsid is unknown to the phylum.
+func Export(ctx context.Context, client *substratewrapper.SubstrateInstanceWrapperCommon, dsid string, out interface{}) error {
+	resp, err := (*client).CallCtx(ctx, exportEndpoint, substratecommon.WithParams(dsid))
+	if err != nil {
+		return fmt.Errorf("private: export %s: %w", dsid, err)
+	}
+	if err := checkResponse(resp); err != nil {
+		return fmt.Errorf("private: export %s: %w", dsid, err)
+	}
+	if out == nil {
+		return nil
+	}
+	return resp.UnmarshalTo(out)
+}
+
+// Purge deletes the private data filed under dsid. It errors if dsid is
+// unknown to the phylum.
+func Purge(ctx context.Context, client *substratewrapper.SubstrateInstanceWrapperCommon, dsid string) error {
+	resp, err := (*client).CallCtx(ctx, purgeEndpoint, substratecommon.WithParams(dsid))
+	if err != nil {
+		return fmt.Errorf("private: purge %s: %w", dsid, err)
+	}
+	return checkResponse(resp)
+}
+
+// ProfileToDSID resolves a profile (the fields extracted by a
+// Transform's ProfilePaths) to the data-subject ID it was filed under, so
+// the caller can later Export or Purge it.
+func ProfileToDSID(ctx context.Context, client *substratewrapper.SubstrateInstanceWrapperCommon, profile []string) (string, error) {
+	resp, err := (*client).CallCtx(ctx, profileToDSIDEndpoint, substratecommon.WithParams(profile))
+	if err != nil {
+		return "", fmt.Errorf("private: profile to dsid: %w", err)
+	}
+	if err := checkResponse(resp); err != nil {
+		return "", fmt.Errorf("private: profile to dsid: %w", err)
+	}
+	var dsid string
+	if err := resp.UnmarshalTo(&dsid); err != nil {
+		return "", fmt.Errorf("private: profile to dsid: decoding response: %w", err)
+	}
+	return dsid, nil
+}
+
+// Option customizes a single Encode/EncodeProto/WrapCall/WrapCallProto
+// (and its corresponding Decode/DecodeProto) call.
+type Option func(*options)
+
+type options struct {
+	keyProvider KeyProvider
+}
+
+func resolveOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithKeyProvider overrides the KeyProvider used to wrap (or unwrap) this
+// call's data-encryption key with kp directly, instead of looking one up
+// by name via the Transform header's KeyProvider field and the global
+// RegisterKeyProvider registry. Use this when a KeyProvider is
+// constructed per-call (e.g. a per-tenant KMS key) rather than shared
+// process-wide under a registered name. A Decode/DecodeProto call must
+// pass the same KeyProvider to recover a message sealed this way, since
+// the envelope carries no name to look it up by.
+func WithKeyProvider(kp KeyProvider) Option {
+	return func(o *options) {
+		o.keyProvider = kp
+	}
+}
+
+// Encode marshals message as JSON and applies transforms, returning a
+// self-describing envelope Decode can later reverse. An empty transforms
+// list returns the marshaled message unwrapped.
+func Encode(ctx context.Context, client *substratewrapper.SubstrateInstanceWrapperCommon, message interface{}, transforms []*Transform, opts ...Option) (json.RawMessage, error) {
+	return encodeMessage(ctx, client, message, transforms, CodecJSON, opts...)
+}
+
+// EncodeProto is Encode for proto.Message values: message is marshaled
+// with proto.Marshal instead of JSON, which is smaller and schema-driven.
+// ProfilePaths are resolved against message's fields via protoreflect
+// rather than a JSON document.
+func EncodeProto(ctx context.Context, client *substratewrapper.SubstrateInstanceWrapperCommon, message proto.Message, transforms []*Transform, opts ...Option) (json.RawMessage, error) {
+	return encodeMessage(ctx, client, message, transforms, CodecProto, opts...)
+}
+
+// Decode reverses Encode/EncodeProto, detecting from the envelope
+// whether transforms were applied and which Codec was used, and
+// unmarshals the recovered message into target. Content that isn't a
+// recognized envelope is treated as an untransformed message and
+// unmarshaled directly, which is how WrapCall decodes a plain endpoint
+// response. Pass the same WithKeyProvider Option Encode/EncodeProto used,
+// if any.
+func Decode(ctx context.Context, client *substratewrapper.SubstrateInstanceWrapperCommon, encoded json.RawMessage, target interface{}, opts ...Option) error {
+	raw, codec, err := decodeEnvelope(ctx, encoded, opts...)
+	if err != nil {
+		return err
+	}
+	return unmarshalCodec(raw, target, codec)
+}
+
+// DecodeProto is Decode for proto.Message targets. It returns an error if
+// encoded was produced with a non-proto Codec.
+func DecodeProto(ctx context.Context, client *substratewrapper.SubstrateInstanceWrapperCommon, encoded json.RawMessage, target proto.Message, opts ...Option) error {
+	return Decode(ctx, client, encoded, target, opts...)
+}
+
+// WrapCall returns a closure that applies transforms to in, calls
+// endpoint with the result, and decodes whatever the phylum returns into
+// out. An empty transforms list passes in through unencoded, for
+// endpoints that don't handle private data at all.
+func WrapCall(ctx context.Context, client *substratewrapper.SubstrateInstanceWrapperCommon, endpoint string, transforms []*Transform, opts ...Option) func(in, out interface{}, configs ...substratecommon.Config) error {
+	return func(in, out interface{}, configs ...substratecommon.Config) error {
+		params, err := encodeMessage(ctx, client, in, transforms, CodecJSON, opts...)
+		if err != nil {
+			return err
+		}
+		return wrapCall(ctx, client, endpoint, params, out, configs, opts...)
+	}
+}
+
+// WrapCallProto is WrapCall for proto.Message values; see EncodeProto.
+func WrapCallProto(ctx context.Context, client *substratewrapper.SubstrateInstanceWrapperCommon, endpoint string, transforms []*Transform, opts ...Option) func(in, out proto.Message, configs ...substratecommon.Config) error {
+	return func(in, out proto.Message, configs ...substratecommon.Config) error {
+		params, err := encodeMessage(ctx, client, in, transforms, CodecProto, opts...)
+		if err != nil {
+			return err
+		}
+		return wrapCall(ctx, client, endpoint, params, out, configs, opts...)
+	}
+}
+
+func wrapCall(ctx context.Context, client *substratewrapper.SubstrateInstanceWrapperCommon, endpoint string, params json.RawMessage, out interface{}, configs []substratecommon.Config, opts ...Option) error {
+	callConfigs := append([]substratecommon.Config{substratecommon.WithParams(params)}, configs...)
+	resp, err := (*client).CallCtx(ctx, endpoint, callConfigs...)
+	if err != nil {
+		return fmt.Errorf("private: wrap %s: %w", endpoint, err)
+	}
+	if err := checkResponse(resp); err != nil {
+		return fmt.Errorf("private: wrap %s: %w", endpoint, err)
+	}
+	if out == nil {
+		return nil
+	}
+	return Decode(ctx, client, resp.ResultJSON, out, opts...)
+}
+
+// WithSeed generates a fresh random seed and carries it to the phylum as
+// transient call data, which the private: phylum library's CSPRNG needs
+// to deterministically derive encryption randomness across endorsing
+// peers. A Transform that encrypts (e.g. EncryptorAES256) fails on the
+// phylum side with a "missing CSPRNG seed" style error if no WithSeed
+// config is supplied.
+func WithSeed() (substratecommon.Config, error) {
+	seed := make([]byte, 32)
+	if _, err := rand.Read(seed); err != nil {
+		return nil, fmt.Errorf("private: generating seed: %w", err)
+	}
+	return substratecommon.WithTransientData("SEED", seed), nil
+}
+
+// checkResponse turns a Response's application-level error fields into a
+// Go error, the same way the rest of this package surfaces errors.
+func checkResponse(resp *substratecommon.Response) error {
+	if resp == nil {
+		return fmt.Errorf("private: empty response")
+	}
+	if resp.HasError {
+		return fmt.Errorf("%s (code %d)", resp.ErrorMessage, resp.ErrorCode)
+	}
+	return nil
+}
+
+// encodeMessage is the shared implementation behind Encode/EncodeProto
+// and WrapCall/WrapCallProto's request side.
+func encodeMessage(ctx context.Context, client *substratewrapper.SubstrateInstanceWrapperCommon, message interface{}, transforms []*Transform, codec Codec, opts ...Option) (json.RawMessage, error) {
+	raw, err := marshalCodec(message, codec)
+	if err != nil {
+		return nil, err
+	}
+	if len(transforms) == 0 {
+		return json.RawMessage(raw), nil
+	}
+	if len(transforms) > 1 {
+		return nil, fmt.Errorf("private: only a single root transform is currently supported, got %d", len(transforms))
+	}
+	tr := transforms[0]
+	if tr.ContextPath != "" && tr.ContextPath != "." {
+		return nil, fmt.Errorf("private: transform context path %q is not supported; only the root path (\".\") is", tr.ContextPath)
+	}
+	if tr.Header == nil {
+		return nil, fmt.Errorf("private: transform is missing a header")
+	}
+
+	var profile json.RawMessage
+	if codec == CodecProto {
+		profile, err = extractProfileProto(message.(proto.Message), tr.Header.ProfilePaths)
+	} else {
+		profile, err = extractProfileJSON(raw, tr.Header.ProfilePaths)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext, wrappedKey, keyRef, err := seal(ctx, raw, tr.Header.Encryptor, tr.Header.Compressor, tr.Header.KeyProvider, resolveOptions(opts).keyProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	env := &mxfEnvelope{
+		V:           mxfVersion,
+		Codec:       codec,
+		Encryptor:   tr.Header.Encryptor,
+		Compressor:  tr.Header.Compressor,
+		Profile:     profile,
+		Ciphertext:  ciphertext,
+		KeyProvider: tr.Header.KeyProvider,
+		KeyRef:      keyRef,
+		WrappedKey:  wrappedKey,
+	}
+	out, err := json.Marshal(env)
+	if err != nil {
+		return nil, fmt.Errorf("private: marshaling envelope: %w", err)
+	}
+	return out, nil
+}
+
+// decodeEnvelope reverses encodeMessage's envelope, if any, returning the
+// recovered plaintext payload and the Codec it was marshaled with.
+// Content that isn't a recognized envelope is passed through unchanged as
+// CodecJSON.
+func decodeEnvelope(ctx context.Context, encoded json.RawMessage, opts ...Option) ([]byte, Codec, error) {
+	var env mxfEnvelope
+	if err := json.Unmarshal(encoded, &env); err != nil || env.V != mxfVersion {
+		return encoded, CodecJSON, nil
+	}
+	raw, err := open(ctx, env.Ciphertext, env.Encryptor, env.Compressor, env.KeyProvider, env.WrappedKey, env.KeyRef, resolveOptions(opts).keyProvider)
+	if err != nil {
+		return nil, "", err
+	}
+	return raw, env.Codec, nil
+}
+
+func marshalCodec(message interface{}, codec Codec) ([]byte, error) {
+	if codec == CodecProto {
+		pm, ok := message.(proto.Message)
+		if !ok {
+			return nil, fmt.Errorf("private: EncodeProto/WrapCallProto require a proto.Message, got %T", message)
+		}
+		raw, err := proto.Marshal(pm)
+		if err != nil {
+			return nil, fmt.Errorf("private: marshaling proto message: %w", err)
+		}
+		return raw, nil
+	}
+	raw, err := json.Marshal(message)
+	if err != nil {
+		return nil, fmt.Errorf("private: marshaling message: %w", err)
+	}
+	return raw, nil
+}
+
+func unmarshalCodec(raw []byte, target interface{}, codec Codec) error {
+	if codec == CodecProto {
+		pm, ok := target.(proto.Message)
+		if !ok {
+			return fmt.Errorf("private: decoding a proto-codec message into %T, which is not a proto.Message; use DecodeProto", target)
+		}
+		if err := proto.Unmarshal(raw, pm); err != nil {
+			return fmt.Errorf("private: unmarshaling proto message: %w", err)
+		}
+		return nil
+	}
+	if err := json.Unmarshal(raw, target); err != nil {
+		return fmt.Errorf("private: decoding message: %w", err)
+	}
+	return nil
+}
+
+// splitPath turns a dotted field path ("." or ".a.b") into its
+// components, with "." (or "") meaning the whole document.
+func splitPath(path string) []string {
+	trimmed := strings.TrimPrefix(path, ".")
+	if trimmed == "" {
+		return nil
+	}
+	return strings.Split(trimmed, ".")
+}
+
+// extractProfileJSON builds the public profile projection of raw (a JSON
+// object) named by paths. Only top-level fields are currently supported.
+func extractProfileJSON(raw []byte, paths []string) (json.RawMessage, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("private: profile paths require a JSON object message: %w", err)
+	}
+	profile := make(map[string]json.RawMessage, len(paths))
+	for _, p := range paths {
+		parts := splitPath(p)
+		if len(parts) == 0 {
+			// "." names the whole document; nothing to single out.
+			continue
+		}
+		if len(parts) > 1 {
+			return nil, fmt.Errorf("private: nested profile path %q is not supported for JSON messages", p)
+		}
+		v, ok := doc[parts[0]]
+		if !ok {
+			return nil, fmt.Errorf("private: message has no field %q for profile path %q", parts[0], p)
+		}
+		profile[parts[0]] = v
+	}
+	return json.Marshal(profile)
+}
+
+// seal compresses then encrypts plaintext per compressor/encryptor, both
+// looked up by name in the Compressor/Encryptor registries. The
+// encryptor's nonce (sized by its NonceSize) is generated here and
+// prepended to the returned ciphertext so open can recover it.
+//
+// If keyProviderName is empty and keyProviderOverride is nil, the
+// encryptor's key comes from symmetricKey, a static local key, same as
+// before KeyProvider existed. Otherwise a fresh data-encryption key is
+// generated for this seal call and wrapped by keyProviderOverride (if
+// non-nil, as set by the WithKeyProvider Option) or else the KeyProvider
+// registered under keyProviderName, returning the wrapped key and its
+// keyRef for the caller to store alongside the ciphertext; open needs
+// both to recover the key via the same KeyProvider's UnwrapDEK.
+func seal(ctx context.Context, plaintext []byte, encryptorName, compressorName, keyProviderName string, keyProviderOverride KeyProvider) (ciphertext, wrappedKey []byte, keyRef string, err error) {
+	compressor, ok := lookupCompressor(compressorName)
+	if !ok {
+		return nil, nil, "", fmt.Errorf("private: unknown compressor %q", compressorName)
+	}
+	compressed, err := compressor.Compress(plaintext)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	encryptor, ok := lookupEncryptor(encryptorName)
+	if !ok {
+		return nil, nil, "", fmt.Errorf("private: unknown encryptor %q", encryptorName)
+	}
+	if encryptor.KeySize() == 0 {
+		sealed, err := encryptor.Seal(nil, nil, compressed, nil)
+		return sealed, nil, "", err
+	}
+
+	var key []byte
+	if keyProviderOverride == nil && keyProviderName == "" {
+		key, err = symmetricKey(encryptorName, encryptor.KeySize())
+		if err != nil {
+			return nil, nil, "", err
+		}
+	} else {
+		kp := keyProviderOverride
+		if kp == nil {
+			var ok bool
+			kp, ok = lookupKeyProvider(keyProviderName)
+			if !ok {
+				return nil, nil, "", fmt.Errorf("private: unknown key provider %q", keyProviderName)
+			}
+		}
+		key = make([]byte, encryptor.KeySize())
+		if _, err := rand.Read(key); err != nil {
+			return nil, nil, "", fmt.Errorf("private: generating data encryption key: %w", err)
+		}
+		wrappedKey, keyRef, err = kp.WrapDEK(ctx, key)
+		if err != nil {
+			return nil, nil, "", fmt.Errorf("private: wrapping data encryption key: %w", err)
+		}
+	}
+
+	nonce := make([]byte, encryptor.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, "", fmt.Errorf("private: %s nonce: %w", encryptorName, err)
+	}
+	sealed, err := encryptor.Seal(key, nonce, compressed, nil)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return append(nonce, sealed...), wrappedKey, keyRef, nil
+}
+
+// open reverses seal. keyProviderOverride, if non-nil (as set by the
+// WithKeyProvider Option), is used to unwrap the data-encryption key
+// instead of looking one up by name via keyProviderName; it must be the
+// same KeyProvider instance passed to WithKeyProvider on the call that
+// sealed this envelope.
+func open(ctx context.Context, ciphertext []byte, encryptorName, compressorName, keyProviderName string, wrappedKey []byte, keyRef string, keyProviderOverride KeyProvider) ([]byte, error) {
+	encryptor, ok := lookupEncryptor(encryptorName)
+	if !ok {
+		return nil, fmt.Errorf("private: unknown encryptor %q", encryptorName)
+	}
+	var compressed []byte
+	if encryptor.KeySize() == 0 {
+		var err error
+		compressed, err = encryptor.Open(nil, nil, ciphertext, nil)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if len(ciphertext) < encryptor.NonceSize() {
+			return nil, fmt.Errorf("private: %s ciphertext is shorter than a nonce", encryptorName)
+		}
+		nonce, sealed := ciphertext[:encryptor.NonceSize()], ciphertext[encryptor.NonceSize():]
+
+		var key []byte
+		var err error
+		if keyProviderOverride == nil && keyProviderName == "" {
+			key, err = symmetricKey(encryptorName, encryptor.KeySize())
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			kp := keyProviderOverride
+			if kp == nil {
+				var ok bool
+				kp, ok = lookupKeyProvider(keyProviderName)
+				if !ok {
+					return nil, fmt.Errorf("private: unknown key provider %q", keyProviderName)
+				}
+			}
+			key, err = kp.UnwrapDEK(ctx, wrappedKey, keyRef)
+			if err != nil {
+				return nil, fmt.Errorf("private: unwrapping data encryption key: %w", err)
+			}
+		}
+
+		compressed, err = encryptor.Open(key, nonce, sealed, nil)
+		if err != nil {
+			return nil, fmt.Errorf("private: %s decrypt: %w", encryptorName, err)
+		}
+	}
+	compressor, ok := lookupCompressor(compressorName)
+	if !ok {
+		return nil, fmt.Errorf("private: unknown compressor %q", compressorName)
+	}
+	return compressor.Decompress(compressed)
+}
@@ -103,7 +103,6 @@ func TestPrivate(t *testing.T) {
 					ContextPath: ".",
 					Header: &private.TransformHeader{
 						ProfilePaths: []string{".fnord"},
-						PrivatePaths: []string{"."},
 						Encryptor:    private.EncryptorAES256,
 						Compressor:   private.CompressorZlib,
 					},
@@ -140,12 +139,11 @@ func TestPrivate(t *testing.T) {
 					ContextPath: ".",
 					Header: &private.TransformHeader{
 						ProfilePaths: []string{".fnord"},
-						PrivatePaths: []string{"."},
 						Encryptor:    private.EncryptorAES256,
 						Compressor:   private.CompressorZlib,
 					},
 				})
-				wrap := private.WrapCall(context.Background(), client, "wrap_all", transforms...)
+				wrap := private.WrapCall(context.Background(), client, "wrap_all", transforms)
 				decodedMessage := struct {
 					Hello string `json:"hello"`
 					Fnord string `json:"fnord"`
@@ -174,7 +172,7 @@ func TestPrivate(t *testing.T) {
 					"fnord",
 				}
 				var transforms []*private.Transform
-				wrap := private.WrapCall(context.Background(), client, "wrap_none", transforms...)
+				wrap := private.WrapCall(context.Background(), client, "wrap_none", transforms)
 				decodedMessage := struct {
 					Hello string `json:"hello"`
 					Fnord string `json:"fnord"`
@@ -200,7 +198,7 @@ func TestPrivate(t *testing.T) {
 					"fnord",
 				}
 				var transforms []*private.Transform
-				wrap := private.WrapCall(context.Background(), client, "wrap_output", transforms...)
+				wrap := private.WrapCall(context.Background(), client, "wrap_output", transforms)
 				decodedMessage := struct {
 					Hello string `json:"hello"`
 					Fnord string `json:"fnord"`
@@ -229,12 +227,11 @@ func TestPrivate(t *testing.T) {
 					ContextPath: ".",
 					Header: &private.TransformHeader{
 						ProfilePaths: []string{".fnord"},
-						PrivatePaths: []string{"."},
 						Encryptor:    private.EncryptorAES256,
 						Compressor:   private.CompressorZlib,
 					},
 				})
-				wrap := private.WrapCall(context.Background(), client, "wrap_input", transforms...)
+				wrap := private.WrapCall(context.Background(), client, "wrap_input", transforms)
 				decodedMessage := struct {
 					Hello string `json:"hello"`
 					Fnord string `json:"fnord"`
@@ -263,12 +260,11 @@ func TestPrivate(t *testing.T) {
 					ContextPath: ".",
 					Header: &private.TransformHeader{
 						ProfilePaths: []string{".fnord"},
-						PrivatePaths: []string{"."},
 						Encryptor:    private.EncryptorAES256,
 						Compressor:   private.CompressorZlib,
 					},
 				})
-				wrap := private.WrapCall(context.Background(), client, "wrap_all", transforms...)
+				wrap := private.WrapCall(context.Background(), client, "wrap_all", transforms)
 				decodedMessage := struct {
 					Hello string `json:"hello"`
 					Fnord string `json:"fnord"`
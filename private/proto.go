@@ -0,0 +1,75 @@
+package private
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// extractProfileProto is extractProfileJSON for proto.Message values:
+// paths are resolved against message's fields via protoreflect, walking
+// into message-kind fields for multi-level paths, rather than against a
+// marshaled JSON document.
+func extractProfileProto(message proto.Message, paths []string) (json.RawMessage, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	profile := make(map[string]interface{}, len(paths))
+	for _, p := range paths {
+		parts := splitPath(p)
+		if len(parts) == 0 {
+			// "." names the whole message; nothing to single out.
+			continue
+		}
+		v, err := protoFieldValue(message.ProtoReflect(), parts)
+		if err != nil {
+			return nil, fmt.Errorf("private: profile path %q: %w", p, err)
+		}
+		profile[strings.Join(parts, ".")] = v
+	}
+	return json.Marshal(profile)
+}
+
+// protoFieldValue resolves a dotted field path against msg, descending
+// into message-kind fields one path component at a time.
+func protoFieldValue(msg protoreflect.Message, parts []string) (interface{}, error) {
+	fd := msg.Descriptor().Fields().ByName(protoreflect.Name(parts[0]))
+	if fd == nil {
+		return nil, fmt.Errorf("message %s has no field %q", msg.Descriptor().FullName(), parts[0])
+	}
+	if fd.IsList() || fd.IsMap() {
+		return nil, fmt.Errorf("field %q is repeated or a map; profile paths do not support resolving into it", parts[0])
+	}
+	v := msg.Get(fd)
+	if len(parts) == 1 {
+		return protoScalarValue(v, fd), nil
+	}
+	if fd.Kind() != protoreflect.MessageKind && fd.Kind() != protoreflect.GroupKind {
+		return nil, fmt.Errorf("field %q is not a message, cannot resolve %q", parts[0], strings.Join(parts[1:], "."))
+	}
+	return protoFieldValue(v.Message(), parts[1:])
+}
+
+// protoScalarValue converts a scalar protoreflect.Value to the
+// corresponding Go value for profile JSON encoding.
+func protoScalarValue(v protoreflect.Value, fd protoreflect.FieldDescriptor) interface{} {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return v.Bool()
+	case protoreflect.StringKind:
+		return v.String()
+	case protoreflect.BytesKind:
+		return v.Bytes()
+	case protoreflect.Int32Kind, protoreflect.Int64Kind, protoreflect.Sint32Kind, protoreflect.Sint64Kind, protoreflect.Sfixed32Kind, protoreflect.Sfixed64Kind:
+		return v.Int()
+	case protoreflect.Uint32Kind, protoreflect.Uint64Kind, protoreflect.Fixed32Kind, protoreflect.Fixed64Kind:
+		return v.Uint()
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return v.Float()
+	default:
+		return v.Interface()
+	}
+}
@@ -0,0 +1,79 @@
+package private_test
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/luthersystems/substratecommon/private"
+)
+
+// TestEncodeDecodeProtoRoundTrip exercises EncodeProto/DecodeProto and the
+// protoreflect-based ProfilePaths resolution against a real protoreflect
+// message, using a well-known proto type so the test needs no
+// repo-specific .proto/generated code.
+func TestEncodeDecodeProtoRoundTrip(t *testing.T) {
+	kp, err := private.NewLocalKeyProvider("test-key", make([]byte, 32))
+	if err != nil {
+		t.Fatalf("NewLocalKeyProvider: %s", err)
+	}
+
+	message := wrapperspb.String("fnord")
+	transforms := []*private.Transform{
+		{
+			ContextPath: ".",
+			Header: &private.TransformHeader{
+				ProfilePaths: []string{"value"},
+				Encryptor:    private.EncryptorAES256,
+				Compressor:   private.CompressorZlib,
+			},
+		},
+	}
+
+	encoded, err := private.EncodeProto(context.Background(), nil, message, transforms, private.WithKeyProvider(kp))
+	if err != nil {
+		t.Fatalf("encode: %s", err)
+	}
+
+	decoded := &wrapperspb.StringValue{}
+	if err := private.DecodeProto(context.Background(), nil, encoded, decoded, private.WithKeyProvider(kp)); err != nil {
+		t.Fatalf("decode: %s", err)
+	}
+	if decoded.GetValue() != message.GetValue() {
+		t.Fatalf("message mismatch, expected: %v != got: %v", message, decoded)
+	}
+}
+
+// TestEncodeProtoProfilePathRepeatedOrMapErrors confirms a ProfilePaths
+// entry naming a repeated or map field fails loudly instead of panicking
+// (a repeated field) or silently profiling an empty object (a map field).
+func TestEncodeProtoProfilePathRepeatedOrMapErrors(t *testing.T) {
+	transforms := func(path string) []*private.Transform {
+		return []*private.Transform{
+			{
+				ContextPath: ".",
+				Header: &private.TransformHeader{
+					ProfilePaths: []string{path},
+					Encryptor:    private.EncryptorNone,
+					Compressor:   private.CompressorNone,
+				},
+			},
+		}
+	}
+
+	t.Run("repeated", func(t *testing.T) {
+		message := &structpb.ListValue{Values: []*structpb.Value{structpb.NewStringValue("a")}}
+		if _, err := private.EncodeProto(context.Background(), nil, message, transforms("values")); err == nil {
+			t.Fatal("expected an error profiling a repeated field")
+		}
+	})
+
+	t.Run("map", func(t *testing.T) {
+		message := &structpb.Struct{Fields: map[string]*structpb.Value{"a": structpb.NewStringValue("b")}}
+		if _, err := private.EncodeProto(context.Background(), nil, message, transforms("fields")); err == nil {
+			t.Fatal("expected an error profiling a map field")
+		}
+	})
+}
@@ -11,6 +11,7 @@ import (
 	"net/rpc"
 	"os"
 	"os/exec"
+	"sync"
 	"time"
 
 	"github.com/golang/protobuf/jsonpb"
@@ -38,29 +39,38 @@ type ConcreteRequestOptions struct {
 	DisableWritePolling bool
 	CCFetchURLDowngrade bool
 	CCFetchURLProxy     string
+
+	// EventChaincodeIDFilter and EventReasonPrefixFilter, when non-empty,
+	// restrict SubscribeBlocks to transactions matching the chaincode ID
+	// and/or reason prefix; the server is expected to filter before
+	// fanning blocks out. Set via WithEventFilter.
+	EventChaincodeIDFilter  string
+	EventReasonPrefixFilter string
 }
 
 // RequestOptions are operated on by the Config functions generated by
 // the With* functions.
 type RequestOptions struct {
-	Log                 *logrus.Logger // not flat!
-	LogFields           logrus.Fields  // not flat!
-	Headers             map[string]string
-	Endpoint            string
-	ID                  string
-	AuthToken           string
-	Params              interface{} // not flat!
-	Transient           map[string][]byte
-	Target              *interface{}                 // not flat!
-	TimestampGenerator  func(context.Context) string // not flat!
-	MSPFilter           []string
-	MinEndorsers        int
-	Creator             string
-	Ctx                 context.Context // not flat!
-	DependentTxID       string
-	DisableWritePolling bool
-	CCFetchURLDowngrade bool
-	CCFetchURLProxy     string
+	Log                     *logrus.Logger // not flat!
+	LogFields               logrus.Fields  // not flat!
+	Headers                 map[string]string
+	Endpoint                string
+	ID                      string
+	AuthToken               string
+	Params                  interface{} // not flat!
+	Transient               map[string][]byte
+	Target                  *interface{}                 // not flat!
+	TimestampGenerator      func(context.Context) string // not flat!
+	MSPFilter               []string
+	MinEndorsers            int
+	Creator                 string
+	Ctx                     context.Context // not flat!
+	DependentTxID           string
+	DisableWritePolling     bool
+	CCFetchURLDowngrade     bool
+	CCFetchURLProxy         string
+	EventChaincodeIDFilter  string
+	EventReasonPrefixFilter string
 }
 
 // Config is a type for a function that can mutate a requestOptions
@@ -240,6 +250,17 @@ func WithCCFetchURLProxy(proxy string) Config {
 	})
 }
 
+// WithEventFilter restricts a SubscribeBlocks subscription to transactions
+// matching chaincodeID and/or having a reason with the given prefix. Either
+// argument may be left "" to leave that dimension unfiltered. Only affects
+// SubscribeBlocks; has no effect on Call/QueryInfo/QueryBlock.
+func WithEventFilter(chaincodeID, reasonPrefix string) Config {
+	return (func(r *RequestOptions) {
+		r.EventChaincodeIDFilter = chaincodeID
+		r.EventReasonPrefixFilter = reasonPrefix
+	})
+}
+
 func tsg(context context.Context, tg func(context.Context) string) string {
 	if tg != nil {
 		return tg(context)
@@ -281,6 +302,9 @@ func FlattenOptions(configs ...Config) (*ConcreteRequestOptions, error) {
 		DisableWritePolling: opt.DisableWritePolling,
 		CCFetchURLDowngrade: opt.CCFetchURLDowngrade,
 		CCFetchURLProxy:     opt.CCFetchURLProxy,
+
+		EventChaincodeIDFilter:  opt.EventChaincodeIDFilter,
+		EventReasonPrefixFilter: opt.EventReasonPrefixFilter,
 	}, nil
 }
 
@@ -350,273 +374,206 @@ type Block struct {
 }
 
 // Substrate is the interface that we're exposing as a plugin.
+//
+// Parameter and result names here are load-bearing: cmd/substrategen reads
+// them (via go/ast, not reflection) to generate the ArgsXxx/RespXxx structs
+// and PluginRPC/PluginRPCServer methods below into substrate_rpc.gen.go. A
+// method tagged "substrate:stream" or "substrate:notimeout" in its doc
+// comment is left out of generation entirely; see the tool's doc comment
+// for what those mean and why SubscribeBlocks/IsTimeoutError use them.
+//
+//go:generate go run ./cmd/substrategen
 type Substrate interface {
-	NewRPC() (string, error)
-	CloseRPC(string) error
-
-	NewMockFrom(string, string, []byte) (string, error)
-	SetCreatorWithAttributesMock(string, string, map[string]string) error
-	SnapshotMock(string) ([]byte, error)
-	CloseMock(string) error
-
-	Init(string, string, *ConcreteRequestOptions) error
-	Call(string, string, *ConcreteRequestOptions) (*Response, error)
-	QueryInfo(string, *ConcreteRequestOptions) (uint64, error)
-	QueryBlock(string, uint64, *ConcreteRequestOptions) (*Block, error)
-
-	// IsTimeoutError doesn't use RPC
+	NewRPC() (tag string, err error)
+	CloseRPC(tag string) error
+	CloseRPCCtx(ctx context.Context, tag string) error
+
+	NewMockFrom(name string, version string, snapshot []byte) (tag string, err error)
+	SetCreatorWithAttributesMock(tag string, creator string, attrs map[string]string) error
+	SnapshotMock(tag string) (snapshot []byte, err error)
+	CloseMock(tag string) error
+	CloseMockCtx(ctx context.Context, tag string) error
+
+	Init(tag string, phylum string, options *ConcreteRequestOptions) error
+	InitCtx(ctx context.Context, tag string, phylum string, options *ConcreteRequestOptions) error
+	Call(tag string, command string, options *ConcreteRequestOptions) (response *Response, err error)
+	CallCtx(ctx context.Context, tag string, command string, options *ConcreteRequestOptions) (response *Response, err error)
+	QueryInfo(tag string, options *ConcreteRequestOptions) (height uint64, err error)
+	QueryInfoCtx(ctx context.Context, tag string, options *ConcreteRequestOptions) (height uint64, err error)
+	QueryBlock(tag string, height uint64, options *ConcreteRequestOptions) (block *Block, err error)
+	QueryBlockCtx(ctx context.Context, tag string, height uint64, options *ConcreteRequestOptions) (block *Block, err error)
+
+	// HealthCheck is a cheap liveness probe; implementations are expected
+	// to echo x back unmodified.
+	HealthCheck(x int) (y int, err error)
+	HealthCheckCtx(ctx context.Context, x int) (y int, err error)
+
+	// SubscribeBlocks streams blocks committed at or after startHeight,
+	// optionally filtered by options.EventChaincodeIDFilter/
+	// EventReasonPrefixFilter (set via WithEventFilter). The returned
+	// channel is closed, and the returned func stops delivering further
+	// blocks and releases the subscription, once either the caller invokes
+	// it or the underlying connection is lost. Callers that need to
+	// resume after a transient failure should re-subscribe from the
+	// height of the last block they received.
+	//
+	// substrate:stream - delivery happens over a MuxBroker/gRPC stream
+	// rather than a single Args/Resp round trip, so this one is still
+	// hand-written; see blockSinkServer and substratecommon_grpc.go.
+	SubscribeBlocks(tag string, startHeight uint64, options *ConcreteRequestOptions) (blocks <-chan *Block, stop func() error, err error)
+
+	// Ping is a minimal liveness probe with no payload, invoked by
+	// SubstrateConnection's supervisor on a timer; see ConnectWithPingInterval.
+	Ping() error
+	PingCtx(ctx context.Context) error
+
+	// Resume re-hydrates the given RPC/mock instance tags after the host
+	// has respawned and reconnected to this plugin following a crash.
+	// Implementations typically restore mock instances from their most
+	// recent SnapshotMock. See ConnectWithAutoRestart.
+	Resume(tags []string) error
+	ResumeCtx(ctx context.Context, tags []string) error
+
+	// ServeHTTP forwards an HTTP request to the named RPC/mock instance and
+	// returns its response, letting a phylum expose an HTTP API over the
+	// plugin channel instead of opening its own listener. See
+	// ConcreteHTTPRequest/ConcreteHTTPResponse and WrapHTTPHandler in
+	// substratecommon_http.go.
+	ServeHTTP(tag string, httpReq *ConcreteHTTPRequest) (httpResp *ConcreteHTTPResponse, err error)
+
+	// IsTimeoutError doesn't use RPC.
+	//
+	// substrate:notimeout - evaluated locally against a deserialized Error,
+	// never itself sent over the wire, so there's nothing to generate.
 	IsTimeoutError(err error) bool
 }
 
-// ArgsNewRPC encodes the arguments to NewRPC
-type ArgsNewRPC struct {
-}
-
-// RespNewRPC encodes the response from NewRPC
-type RespNewRPC struct {
-	Tag string
-	Err *Error
-}
-
-// ArgsCloseRPC encodes the arguments to CloseRPC
-type ArgsCloseRPC struct {
-	Tag string
-}
-
-// RespCloseRPC encodes the response from CloseRPC
-type RespCloseRPC struct {
-	Err *Error
-}
-
-// ArgsNewMockFrom encodes the arguments to NewMockFrom
-type ArgsNewMockFrom struct {
-	Name     string
-	Version  string
-	Snapshot []byte
+// ArgsSubscribeBlocks encodes the arguments to SubscribeBlocks. BrokerID
+// identifies the net/rpc MuxBroker channel the server should dial to push
+// blocks back to the client via BlockSinkServer.
+type ArgsSubscribeBlocks struct {
+	Tag         string
+	StartHeight uint64
+	Options     *ConcreteRequestOptions
+	BrokerID    uint32
 }
 
-// RespNewMockFrom encodes the response from NewMockFrom
-type RespNewMockFrom struct {
-	Tag string
+// RespSubscribeBlocks encodes the response from SubscribeBlocks. The
+// subscription itself is acknowledged synchronously; blocks are delivered
+// asynchronously over the BrokerID channel until the client closes it.
+type RespSubscribeBlocks struct {
 	Err *Error
 }
 
-// ArgsSetCreatorWithAttributesMock encodes the arguments to SetCreatorWithAttributesMock
-type ArgsSetCreatorWithAttributesMock struct {
-	Tag     string
-	Creator string
-	Attrs   map[string]string
-}
-
-// RespSetCreatorWithAttributesMock encodes the response from SetCreatorWithAttributesMock
-type RespSetCreatorWithAttributesMock struct {
-	Err *Error
-}
-
-// ArgsSnapshotMock encodes the arguments to SnapshotMock
-type ArgsSnapshotMock struct {
-	Tag string
-}
-
-// RespSnapshotMock encodes the response from SnapshotMock
-type RespSnapshotMock struct {
-	Snapshot []byte
-	Err      *Error
-}
-
-// ArgsCloseMock encodes the arguments to CloseMock
-type ArgsCloseMock struct {
-	Tag string
-}
-
-// RespCloseMock encodes the response from CloseMock
-type RespCloseMock struct {
-	Err *Error
-}
-
-// ArgsInit encodes the arguments to Init
-type ArgsInit struct {
-	Tag     string
-	Phylum  string
-	Options *ConcreteRequestOptions
-}
-
-// RespInit encodes the response from Init
-type RespInit struct {
-	Err *Error
-}
-
-// ArgsCall encodes the arguments to Call
-type ArgsCall struct {
-	Tag     string
-	Command string
-	Options *ConcreteRequestOptions
-}
-
-// RespCall encodes the response from Call
-type RespCall struct {
-	Response *Response
-	Err      *Error
-}
-
-// ArgsQueryInfo encodes the arguments to QueryInfo
-type ArgsQueryInfo struct {
-	Tag     string
-	Options *ConcreteRequestOptions
-}
-
-// RespQueryInfo encodes the response from QueryInfo
-type RespQueryInfo struct {
-	Height uint64
-	Err    *Error
-}
-
-// ArgsQueryBlock encodes the arguments to QueryBlock
-type ArgsQueryBlock struct {
-	Tag     string
-	Height  uint64
-	Options *ConcreteRequestOptions
-}
-
-// RespQueryBlock encodes the response from QueryBlock
-type RespQueryBlock struct {
+// ArgsBlockSinkPush encodes a single block pushed from the plugin side of a
+// SubscribeBlocks subscription back to the client, over the broker
+// connection named in ArgsSubscribeBlocks.BrokerID.
+type ArgsBlockSinkPush struct {
 	Block *Block
-	Err   *Error
 }
 
-// PluginRPC is an implementation that talks over RPC
-type PluginRPC struct{ client *rpc.Client }
-
-var errRPC = fmt.Errorf("RPC failure")
+// RespBlockSinkPush encodes the response to ArgsBlockSinkPush.
+type RespBlockSinkPush struct{}
 
-// NewRPC forwards the call
-func (g *PluginRPC) NewRPC() (string, error) {
-	var resp RespNewRPC
-	err := g.client.Call("Plugin.NewRPC", &ArgsNewRPC{}, &resp)
-	if err != nil {
-		return "", errRPC
-	}
-	if resp.Err != nil {
-		return "", resp.Err
-	}
-	return resp.Tag, nil
-}
-
-// CloseRPC forwards the call
-func (g *PluginRPC) CloseRPC(tag string) error {
-	var resp RespCloseRPC
-	err := g.client.Call("Plugin.CloseRPC", &ArgsCloseRPC{Tag: tag}, &resp)
-	if err != nil {
-		return errRPC
-	}
-	if resp.Err != nil {
-		return resp.Err
-	}
-	return nil
-}
-
-// NewMockFrom forwards the call
-func (g *PluginRPC) NewMockFrom(name string, version string, snapshot []byte) (string, error) {
-	var resp RespNewMockFrom
-	err := g.client.Call("Plugin.NewMockFrom", &ArgsNewMockFrom{Name: name, Version: version, Snapshot: snapshot}, &resp)
-	if err != nil {
-		return "", errRPC
-	}
-	if resp.Err != nil {
-		return "", resp.Err
-	}
-	return resp.Tag, nil
-}
-
-// SetCreatorWithAttributesMock forwards the call
-func (g *PluginRPC) SetCreatorWithAttributesMock(tag string, creator string, attrs map[string]string) error {
-	var resp RespSetCreatorWithAttributesMock
-	err := g.client.Call("Plugin.SetCreatorWithAttributesMock", &ArgsSetCreatorWithAttributesMock{Tag: tag, Creator: creator, Attrs: attrs}, &resp)
-	if err != nil {
-		return errRPC
-	}
-	if resp.Err != nil {
-		return resp.Err
-	}
-	return nil
+// PluginRPC is an implementation that talks over RPC
+type PluginRPC struct {
+	client *rpc.Client
+	broker *plugin.MuxBroker
 }
 
-// SnapshotMock forwards the call
-func (g *PluginRPC) SnapshotMock(tag string) ([]byte, error) {
-	var resp RespSnapshotMock
-	err := g.client.Call("Plugin.SnapshotMock", &ArgsSnapshotMock{Tag: tag}, &resp)
-	if err != nil {
-		return nil, errRPC
-	}
-	if resp.Err != nil {
-		return nil, resp.Err
-	}
-	return resp.Snapshot, nil
-}
+var errRPC = fmt.Errorf("RPC failure")
 
-// CloseMock forwards the call
-func (g *PluginRPC) CloseMock(tag string) error {
-	var resp RespCloseMock
-	err := g.client.Call("Plugin.CloseMock", &ArgsCloseMock{Tag: tag}, &resp)
-	if err != nil {
-		return errRPC
+// callCtx forwards the call asynchronously so ctx cancellation/deadlines can
+// unblock the caller even though net/rpc itself is not context-aware.
+func (g *PluginRPC) callCtx(ctx context.Context, serviceMethod string, args interface{}, reply interface{}) error {
+	call := g.client.Go(serviceMethod, args, reply, make(chan *rpc.Call, 1))
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case done := <-call.Done:
+		return done.Error
 	}
-	if resp.Err != nil {
-		return resp.Err
-	}
-	return nil
 }
 
-// Init forwards the call
-func (g *PluginRPC) Init(tag string, phylum string, options *ConcreteRequestOptions) error {
-	var resp RespInit
-	err := g.client.Call("Plugin.Init", &ArgsInit{Tag: tag, Phylum: phylum, Options: options}, &resp)
-	if err != nil {
-		return errRPC
-	}
-	if resp.Err != nil {
-		return resp.Err
+// errSubscriptionClosed is returned by blockSinkServer.Push once the
+// client has invoked a SubscribeBlocks subscription's stop func, so the
+// plugin-side push loop in PluginRPCServer.SubscribeBlocks sees an error,
+// stops pushing, and runs its deferred stop() to release the real
+// subscription instead of spinning forever.
+var errSubscriptionClosed = fmt.Errorf("substratecommon: block subscription closed")
+
+// blockSinkServer is dispensed over the MuxBroker by SubscribeBlocks so the
+// plugin side can push blocks back to the client by calling
+// BlockSink.Push. It only ever forwards into sink; ch itself is owned and
+// closed exclusively by the forwarding goroutine started in
+// PluginRPC.SubscribeBlocks, so closing it here could race a concurrent
+// Push.
+type blockSinkServer struct {
+	sink chan<- *Block
+	done <-chan struct{}
+}
+
+// Push delivers a single block. It blocks until the forwarding goroutine
+// has accepted it or the subscription has been stopped, in which case it
+// returns errSubscriptionClosed.
+func (b *blockSinkServer) Push(args *ArgsBlockSinkPush, resp *RespBlockSinkPush) error {
+	select {
+	case b.sink <- args.Block:
+		return nil
+	case <-b.done:
+		return errSubscriptionClosed
 	}
-	return nil
 }
 
-// Call forwards the call
-func (g *PluginRPC) Call(tag string, command string, options *ConcreteRequestOptions) (*Response, error) {
-	var resp RespCall
-	err := g.client.Call("Plugin.Call", &ArgsCall{Tag: tag, Command: command, Options: options}, &resp)
+// SubscribeBlocks forwards the call. Blocks are delivered asynchronously
+// over a MuxBroker channel reserved for the duration of the subscription;
+// see blockSinkServer and PluginRPCServer.SubscribeBlocks. A dedicated
+// goroutine is the sole writer to (and closer of) the returned channel,
+// mirroring the single-writer-closes pattern grpcSubstrateClient.
+// SubscribeBlocks uses, so stop() can never race a concurrent send on it.
+func (g *PluginRPC) SubscribeBlocks(tag string, startHeight uint64, options *ConcreteRequestOptions) (<-chan *Block, func() error, error) {
+	id := g.broker.NextId()
+	sink := make(chan *Block)
+	done := make(chan struct{})
+	go g.broker.AcceptAndServe(id, &blockSinkServer{sink: sink, done: done})
+
+	var resp RespSubscribeBlocks
+	err := g.client.Call("Plugin.SubscribeBlocks", &ArgsSubscribeBlocks{Tag: tag, StartHeight: startHeight, Options: options, BrokerID: id}, &resp)
 	if err != nil {
-		return nil, errRPC
+		close(done)
+		return nil, nil, errRPC
 	}
 	if resp.Err != nil {
-		return nil, resp.Err
+		close(done)
+		return nil, nil, resp.Err
 	}
-	return resp.Response, nil
-}
 
-// QueryInfo forwards the call
-func (g *PluginRPC) QueryInfo(tag string, options *ConcreteRequestOptions) (uint64, error) {
-	var resp RespQueryInfo
-	err := g.client.Call("Plugin.QueryInfo", &ArgsQueryInfo{Tag: tag, Options: options}, &resp)
-	if err != nil {
-		return 0, errRPC
-	}
-	if resp.Err != nil {
-		return 0, resp.Err
-	}
-	return resp.Height, nil
-}
+	ch := make(chan *Block)
+	go func() {
+		defer close(ch)
+		for {
+			select {
+			case block, ok := <-sink:
+				if !ok {
+					return
+				}
+				select {
+				case ch <- block:
+				case <-done:
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
 
-// QueryBlock forwards the call
-func (g *PluginRPC) QueryBlock(tag string, height uint64, options *ConcreteRequestOptions) (*Block, error) {
-	var resp RespQueryBlock
-	err := g.client.Call("Plugin.QueryInfo", &ArgsQueryBlock{Tag: tag, Height: height, Options: options}, &resp)
-	if err != nil {
-		return nil, errRPC
-	}
-	if resp.Err != nil {
-		return nil, resp.Err
+	var closeOnce sync.Once
+	stop := func() error {
+		closeOnce.Do(func() { close(done) })
+		return nil
 	}
-	return resp.Block, nil
+	return ch, stop, nil
 }
 
 // IsTimeoutError checks if the error is a timeout error. This is done locally.
@@ -631,7 +588,8 @@ func (g *PluginRPC) IsTimeoutError(err error) bool {
 // conforming to the requirements of net/rpc
 type PluginRPCServer struct {
 	// This is the real implementation
-	Impl Substrate
+	Impl   Substrate
+	broker *plugin.MuxBroker
 }
 
 func (s *PluginRPCServer) newError(err error) *Error {
@@ -639,117 +597,45 @@ func (s *PluginRPCServer) newError(err error) *Error {
 	return &Error{IsTimeoutError: b, Diagnostic: err.Error()}
 }
 
-// NewRPC forwards the call
-func (s *PluginRPCServer) NewRPC(args *ArgsNewRPC, resp *RespNewRPC) error {
-	tag, err := s.Impl.NewRPC()
-	if err != nil {
-		resp.Err = s.newError(err)
-		return nil
-	}
-	resp.Tag = tag
-	return nil
-}
-
-// CloseRPC forwards the call
-func (s *PluginRPCServer) CloseRPC(args *ArgsCloseRPC, resp *RespCloseRPC) error {
-	err := s.Impl.CloseRPC(args.Tag)
+// SubscribeBlocks forwards the call, dialing the client's broker channel and
+// pumping blocks from Impl.SubscribeBlocks into it until the subscription's
+// channel closes or the broker connection is lost.
+func (s *PluginRPCServer) SubscribeBlocks(args *ArgsSubscribeBlocks, resp *RespSubscribeBlocks) error {
+	blocks, stop, err := s.Impl.SubscribeBlocks(args.Tag, args.StartHeight, args.Options)
 	if err != nil {
 		resp.Err = s.newError(err)
 		return nil
 	}
-	return nil
-}
 
-// NewMockFrom forwards the call
-func (s *PluginRPCServer) NewMockFrom(args *ArgsNewMockFrom, resp *RespNewMockFrom) error {
-	tag, err := s.Impl.NewMockFrom(args.Name, args.Version, args.Snapshot)
+	conn, err := s.broker.Dial(args.BrokerID)
 	if err != nil {
+		stop()
 		resp.Err = s.newError(err)
 		return nil
 	}
-	resp.Tag = tag
-	return nil
-}
 
-// SetCreatorWithAttributesMock forwards the call
-func (s *PluginRPCServer) SetCreatorWithAttributesMock(args *ArgsSetCreatorWithAttributesMock, resp *RespSetCreatorWithAttributesMock) error {
-	err := s.Impl.SetCreatorWithAttributesMock(args.Tag, args.Creator, args.Attrs)
-	if err != nil {
-		resp.Err = s.newError(err)
-		return nil
-	}
-	return nil
-}
-
-// SnapshotMock forwards the call
-func (s *PluginRPCServer) SnapshotMock(args *ArgsSnapshotMock, resp *RespSnapshotMock) error {
-	dat, err := s.Impl.SnapshotMock(args.Tag)
-	if err != nil {
-		resp.Err = s.newError(err)
-		return nil
-	}
-	resp.Snapshot = dat
-	return nil
-}
-
-// CloseMock forwards the call
-func (s *PluginRPCServer) CloseMock(args *ArgsCloseMock, resp *RespCloseMock) error {
-	err := s.Impl.CloseMock(args.Tag)
-	if err != nil {
-		resp.Err = s.newError(err)
-		return nil
-	}
-	return nil
-}
-
-// Init forwards the call
-func (s *PluginRPCServer) Init(args *ArgsInit, resp *RespInit) error {
-	err := s.Impl.Init(args.Tag, args.Phylum, args.Options)
-	if err != nil {
-		resp.Err = s.newError(err)
-		return nil
-	}
-	return nil
-}
-
-// Call forwards the call
-func (s *PluginRPCServer) Call(args *ArgsCall, resp *RespCall) error {
-	res, err := s.Impl.Call(args.Tag, args.Command, args.Options)
-	if err != nil {
-		resp.Err = s.newError(err)
-		return nil
-	}
-	resp.Response = res
-	return nil
-}
-
-// QueryInfo forwards the call
-func (s *PluginRPCServer) QueryInfo(args *ArgsQueryInfo, resp *RespQueryInfo) error {
-	height, err := s.Impl.QueryInfo(args.Tag, args.Options)
-	if err != nil {
-		resp.Err = s.newError(err)
-		return nil
-	}
-	resp.Height = height
-	return nil
-}
+	go func() {
+		defer conn.Close()
+		defer stop()
+		sink := rpc.NewClient(conn)
+		defer sink.Close()
+		for block := range blocks {
+			var pushResp RespBlockSinkPush
+			if err := sink.Call("BlockSink.Push", &ArgsBlockSinkPush{Block: block}, &pushResp); err != nil {
+				return
+			}
+		}
+	}()
 
-// QueryBlock forwards the call
-func (s *PluginRPCServer) QueryBlock(args *ArgsQueryBlock, resp *RespQueryBlock) error {
-	block, err := s.Impl.QueryBlock(args.Tag, args.Height, args.Options)
-	if err != nil {
-		resp.Err = s.newError(err)
-		return nil
-	}
-	resp.Block = block
 	return nil
 }
 
 // Plugin is the implementation of plugin.Plugin so we can
 // serve/consume this.
 //
-// Ignore MuxBroker. That is used to create more multiplexed streams on our
-// plugin connection and is a more advanced use case.
+// The MuxBroker is used by SubscribeBlocks to open a second, server-pushed
+// connection for streaming blocks back to the client; see
+// PluginRPC.SubscribeBlocks and PluginRPCServer.SubscribeBlocks.
 type Plugin struct {
 	// Impl Injection
 	Impl Substrate
@@ -757,14 +643,14 @@ type Plugin struct {
 
 // Server returns an RPC server for this plugin type. We construct a
 // PluginRPCServer for this.
-func (p *Plugin) Server(*plugin.MuxBroker) (interface{}, error) {
-	return &PluginRPCServer{Impl: p.Impl}, nil
+func (p *Plugin) Server(b *plugin.MuxBroker) (interface{}, error) {
+	return &PluginRPCServer{Impl: p.Impl, broker: b}, nil
 }
 
 // Client returns an implementation of our interface that communicates
 // over an RPC client. We return PluginRPC for this.
 func (Plugin) Client(b *plugin.MuxBroker, c *rpc.Client) (interface{}, error) {
-	return &PluginRPC{client: c}, nil
+	return &PluginRPC{client: c, broker: b}, nil
 }
 
 // EncodePhylumBytes encodes a phylum in the manner expected by
@@ -783,7 +669,10 @@ var handshakeConfig = plugin.HandshakeConfig{
 	MagicCookieValue: "substratehcp1",
 }
 
-// pluginMap is the map of plugins we can dispense.
+// pluginMap is the map of plugins we can dispense. Plugin implements both
+// plugin.Plugin (net/rpc) and plugin.GRPCPlugin (gRPC, see
+// substratecommon_grpc.go), so the same entry serves whichever transport
+// ConnectWithProtocol negotiates.
 var pluginMap = map[string]plugin.Plugin{
 	"substrate": &Plugin{},
 }
@@ -792,6 +681,10 @@ type connectOption struct {
 	level        hclog.Level
 	command      string
 	attachStdamp io.Writer
+	protocols    []plugin.Protocol
+	autoRestart  bool
+	pingInterval time.Duration
+	sandbox      *SandboxConfig
 }
 
 // ConnectOption represents the type of a builder action for connectOption
@@ -821,67 +714,303 @@ func ConnectWithAttachStdamp(attachStdamp io.Writer) func(co *connectOption) err
 	})
 }
 
-type SubstrateConnection struct {
-	client    *plugin.Client
-	substrate Substrate
+// ConnectWithProtocol restricts the negotiated plugin transport to the given
+// protocols, in preference order (go-plugin picks the first one both sides
+// advertise). The default advertises both plugin.ProtocolGRPC and
+// plugin.ProtocolNetRPC, preferring gRPC, so old net/rpc-only plugins keep
+// working unmodified.
+func ConnectWithProtocol(protocols ...plugin.Protocol) func(co *connectOption) error {
+	return (func(co *connectOption) error {
+		co.protocols = protocols
+		return nil
+	})
 }
 
-// NewSubstrateConnection connects to a plugin in the background.
-func NewSubstrateConnection(opts ...ConnectOption) (*SubstrateConnection, error) {
-	co := &connectOption{level: hclog.Debug, attachStdamp: nil}
+// ConnectWithAutoRestart enables the connection's supervisor to respawn the
+// plugin process (with the original connectOption) if it crashes, then call
+// Substrate.Resume with the tags of every RPC/mock instance that was active
+// at the time of the crash. Requires ConnectWithPingInterval to be set (or
+// its default) so the supervisor is actually running.
+func ConnectWithAutoRestart(enabled bool) func(co *connectOption) error {
+	return (func(co *connectOption) error {
+		co.autoRestart = enabled
+		return nil
+	})
+}
 
-	for _, opt := range opts {
-		if err := opt(co); err != nil {
-			panic(err)
-		}
+// ConnectWithPingInterval sets how often the connection's supervisor pings
+// the plugin and polls for process exit. The default is 5s; a zero or
+// negative interval disables the supervisor entirely (and with it,
+// auto-restart and Health()/Events() reporting anything but HealthReady).
+func ConnectWithPingInterval(interval time.Duration) func(co *connectOption) error {
+	return (func(co *connectOption) error {
+		co.pingInterval = interval
+		return nil
+	})
+}
+
+// HealthState is the lifecycle state of a SubstrateConnection as tracked by
+// its supervisor goroutine.
+type HealthState int
+
+// HealthState values, in the order a connection normally passes through
+// them. A crash without ConnectWithAutoRestart(true) ends in HealthCrashed;
+// with it, a successful respawn moves to HealthRestarted and pinging
+// resumes from there.
+const (
+	HealthStarting HealthState = iota
+	HealthReady
+	HealthUnhealthy
+	HealthCrashed
+	HealthRestarted
+)
+
+func (h HealthState) String() string {
+	switch h {
+	case HealthStarting:
+		return "Starting"
+	case HealthReady:
+		return "Ready"
+	case HealthUnhealthy:
+		return "Unhealthy"
+	case HealthCrashed:
+		return "Crashed"
+	case HealthRestarted:
+		return "Restarted"
+	default:
+		return "Unknown"
 	}
+}
+
+// HealthEvent is published to a SubstrateConnection's Events channel every
+// time its supervisor observes a HealthState transition.
+type HealthEvent struct {
+	State HealthState
+	Err   error
+}
+
+type SubstrateConnection struct {
+	co *connectOption
+	// substrate is a stable wrapper; see trackingSubstrate. GetSubstrate
+	// keeps returning the same value across a supervisor-driven restart.
+	substrate *trackingSubstrate
+
+	mu     sync.Mutex
+	client *plugin.Client // guarded by mu; use getClient/setClientUnlessClosing
+	closed bool           // guarded by mu; set by Close
+	health HealthState
+	events chan HealthEvent
 
-	// Create an hclog.Logger
+	stopSupervisor chan struct{}
+}
+
+// dialPlugin launches the plugin process described by co and dispenses its
+// Substrate implementation. Factored out of NewSubstrateConnection so the
+// supervisor can use the same logic to respawn after a crash.
+func dialPlugin(co *connectOption) (*plugin.Client, Substrate, error) {
 	logger := hclog.New(&hclog.LoggerOptions{
 		Name:   "plugin",
 		Output: os.Stdout,
 		Level:  co.level,
 	})
 
+	cmd := exec.Command(co.command)
+	if co.sandbox != nil {
+		if err := applySandbox(cmd, co.sandbox, logger); err != nil {
+			return nil, nil, fmt.Errorf("substratecommon: sandboxing plugin: %w", err)
+		}
+	}
+
 	// We're a host! Start by launching the plugin process.
 	client := plugin.NewClient(&plugin.ClientConfig{
-		HandshakeConfig: handshakeConfig,
-		Plugins:         pluginMap,
-		Cmd:             exec.Command(co.command),
-		Logger:          logger,
-		Stderr:          co.attachStdamp,
-		SyncStdout:      co.attachStdamp,
-		SyncStderr:      co.attachStdamp,
+		HandshakeConfig:  handshakeConfig,
+		Plugins:          pluginMap,
+		Cmd:              cmd,
+		Logger:           logger,
+		Stderr:           co.attachStdamp,
+		SyncStdout:       co.attachStdamp,
+		SyncStderr:       co.attachStdamp,
+		AllowedProtocols: co.protocols,
 	})
 
-	// Connect via RPC
 	rpcClient, err := client.Client()
 	if err != nil {
-		log.Fatal(err)
+		client.Kill()
+		return nil, nil, err
 	}
 
-	// Request the plugin
 	raw, err := rpcClient.Dispense("substrate")
 	if err != nil {
-		log.Fatal(err)
+		client.Kill()
+		return nil, nil, err
 	}
 
 	// This feels like a normal interface implementation but is in
-	// fact over an RPC connection.
-	substrate := raw.(Substrate)
+	// fact over an RPC or gRPC connection.
+	return client, raw.(Substrate), nil
+}
+
+// NewSubstrateConnection connects to a plugin in the background.
+func NewSubstrateConnection(opts ...ConnectOption) (*SubstrateConnection, error) {
+	co := &connectOption{
+		level:        hclog.Debug,
+		attachStdamp: nil,
+		protocols:    []plugin.Protocol{plugin.ProtocolGRPC, plugin.ProtocolNetRPC},
+		pingInterval: 5 * time.Second,
+	}
+
+	for _, opt := range opts {
+		if err := opt(co); err != nil {
+			panic(err)
+		}
+	}
+
+	client, substrate, err := dialPlugin(co)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	s := &SubstrateConnection{
+		co:             co,
+		client:         client,
+		substrate:      newTrackingSubstrate(substrate),
+		health:         HealthReady,
+		events:         make(chan HealthEvent, 16),
+		stopSupervisor: make(chan struct{}),
+	}
 
-	return &SubstrateConnection{client: client, substrate: substrate}, nil
+	if co.pingInterval > 0 {
+		go s.supervise()
+	}
+
+	return s, nil
 }
 
 // GetSubstrate returns the Substrate interface associated with a
-// connection.
+// connection. The returned value remains valid across a supervisor-driven
+// restart; it transparently forwards to whichever underlying plugin
+// process is currently live.
 func (s *SubstrateConnection) GetSubstrate() Substrate {
 	return s.substrate
 }
 
+// Health returns the connection's current lifecycle state.
+func (s *SubstrateConnection) Health() HealthState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.health
+}
+
+// Events returns a channel of HealthState transitions. It is never closed;
+// callers should stop reading once Close has been called.
+func (s *SubstrateConnection) Events() <-chan HealthEvent {
+	return s.events
+}
+
+func (s *SubstrateConnection) setHealth(state HealthState, err error) {
+	s.mu.Lock()
+	s.health = state
+	s.mu.Unlock()
+	select {
+	case s.events <- HealthEvent{State: state, Err: err}:
+	default:
+		// Events is a best-effort feed; a slow/absent reader shouldn't
+		// block the supervisor.
+	}
+}
+
+// getClient returns the plugin.Client for whichever process is currently
+// live, guarding against a concurrent restart() swapping it out.
+func (s *SubstrateConnection) getClient() *plugin.Client {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.client
+}
+
+// setClientUnlessClosing installs client as the current plugin process and
+// reports true, unless Close has already run. If Close already ran, it
+// leaves s.client alone (Close already killed it) and reports false so the
+// caller kills the just-dialed client itself instead of leaking it.
+func (s *SubstrateConnection) setClientUnlessClosing(client *plugin.Client) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+	s.client = client
+	return true
+}
+
+// supervise pings the plugin on co.pingInterval and watches for process
+// exit, restarting (if enabled) and reporting HealthEvents throughout.
+func (s *SubstrateConnection) supervise() {
+	ticker := time.NewTicker(s.co.pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopSupervisor:
+			return
+		case <-ticker.C:
+		}
+
+		if s.getClient().Exited() {
+			s.setHealth(HealthCrashed, fmt.Errorf("plugin process exited"))
+			if !s.co.autoRestart {
+				return
+			}
+			if !s.restart() {
+				return
+			}
+			continue
+		}
+
+		if err := s.substrate.Ping(); err != nil {
+			s.setHealth(HealthUnhealthy, err)
+			continue
+		}
+		s.setHealth(HealthReady, nil)
+	}
+}
+
+// restart respawns the plugin process and resumes the tags that were active
+// at the time of the crash. It returns false if the supervisor should stop
+// (the restart itself failed).
+func (s *SubstrateConnection) restart() bool {
+	tags := s.substrate.tagList()
+
+	client, substrate, err := dialPlugin(s.co)
+	if err != nil {
+		s.setHealth(HealthCrashed, err)
+		return false
+	}
+
+	if err := substrate.Resume(tags); err != nil {
+		s.setHealth(HealthCrashed, err)
+		client.Kill()
+		return false
+	}
+
+	if !s.setClientUnlessClosing(client) {
+		// Close ran concurrently with this restart and already killed the
+		// pre-crash client; kill the one we just dialed instead of
+		// leaking the subprocess, and stop the supervisor.
+		client.Kill()
+		return false
+	}
+	s.substrate.swap(substrate)
+	s.setHealth(HealthRestarted, nil)
+	return true
+}
+
 // Close closes a connection.
 func (s *SubstrateConnection) Close() error {
-	s.client.Kill()
+	close(s.stopSupervisor)
+	s.mu.Lock()
+	s.closed = true
+	client := s.client
+	s.mu.Unlock()
+	client.Kill()
 	return nil
 }
 
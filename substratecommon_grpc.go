@@ -0,0 +1,563 @@
+package substratecommon
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
+
+	"github.com/luthersystems/substratecommon/grpcproto"
+)
+
+// GRPCServer registers the gRPC implementation of this plugin type. It lets
+// Plugin satisfy plugin.GRPCPlugin alongside the net/rpc Server/Client pair
+// above, so a single pluginMap entry can be dispensed over either transport
+// depending on what ConnectWithProtocol negotiates.
+func (p *Plugin) GRPCServer(broker *plugin.GRPCBroker, s *grpc.Server) error {
+	grpcproto.RegisterSubstrateServiceServer(s, &grpcSubstrateServer{impl: p.Impl})
+	return nil
+}
+
+// GRPCClient returns an implementation of Substrate that communicates over
+// the given gRPC connection.
+func (Plugin) GRPCClient(ctx context.Context, broker *plugin.GRPCBroker, conn *grpc.ClientConn) (interface{}, error) {
+	return &grpcSubstrateClient{client: grpcproto.NewSubstrateServiceClient(conn)}, nil
+}
+
+// grpcSubstrateServer adapts a Substrate implementation to
+// grpcproto.SubstrateServiceServer.
+type grpcSubstrateServer struct {
+	grpcproto.UnimplementedSubstrateServiceServer
+	impl Substrate
+}
+
+func (s *grpcSubstrateServer) newError(err error) *grpcproto.Error {
+	return toProtoError(&Error{IsTimeoutError: s.impl.IsTimeoutError(err), Diagnostic: err.Error()})
+}
+
+func (s *grpcSubstrateServer) NewRPC(ctx context.Context, req *grpcproto.NewRPCRequest) (*grpcproto.NewRPCResponse, error) {
+	tag, err := s.impl.NewRPC()
+	if err != nil {
+		return &grpcproto.NewRPCResponse{Err: s.newError(err)}, nil
+	}
+	return &grpcproto.NewRPCResponse{Tag: tag}, nil
+}
+
+func (s *grpcSubstrateServer) CloseRPC(ctx context.Context, req *grpcproto.CloseRPCRequest) (*grpcproto.CloseRPCResponse, error) {
+	if err := s.impl.CloseRPCCtx(ctx, req.Tag); err != nil {
+		return &grpcproto.CloseRPCResponse{Err: s.newError(err)}, nil
+	}
+	return &grpcproto.CloseRPCResponse{}, nil
+}
+
+func (s *grpcSubstrateServer) NewMockFrom(ctx context.Context, req *grpcproto.NewMockFromRequest) (*grpcproto.NewMockFromResponse, error) {
+	tag, err := s.impl.NewMockFrom(req.Name, req.Version, req.Snapshot)
+	if err != nil {
+		return &grpcproto.NewMockFromResponse{Err: s.newError(err)}, nil
+	}
+	return &grpcproto.NewMockFromResponse{Tag: tag}, nil
+}
+
+func (s *grpcSubstrateServer) SetCreatorWithAttributesMock(ctx context.Context, req *grpcproto.SetCreatorWithAttributesMockRequest) (*grpcproto.SetCreatorWithAttributesMockResponse, error) {
+	if err := s.impl.SetCreatorWithAttributesMock(req.Tag, req.Creator, req.Attrs); err != nil {
+		return &grpcproto.SetCreatorWithAttributesMockResponse{Err: s.newError(err)}, nil
+	}
+	return &grpcproto.SetCreatorWithAttributesMockResponse{}, nil
+}
+
+func (s *grpcSubstrateServer) SnapshotMock(ctx context.Context, req *grpcproto.SnapshotMockRequest) (*grpcproto.SnapshotMockResponse, error) {
+	snapshot, err := s.impl.SnapshotMock(req.Tag)
+	if err != nil {
+		return &grpcproto.SnapshotMockResponse{Err: s.newError(err)}, nil
+	}
+	return &grpcproto.SnapshotMockResponse{Snapshot: snapshot}, nil
+}
+
+func (s *grpcSubstrateServer) CloseMock(ctx context.Context, req *grpcproto.CloseMockRequest) (*grpcproto.CloseMockResponse, error) {
+	if err := s.impl.CloseMockCtx(ctx, req.Tag); err != nil {
+		return &grpcproto.CloseMockResponse{Err: s.newError(err)}, nil
+	}
+	return &grpcproto.CloseMockResponse{}, nil
+}
+
+func (s *grpcSubstrateServer) Init(ctx context.Context, req *grpcproto.InitRequest) (*grpcproto.InitResponse, error) {
+	if err := s.impl.InitCtx(ctx, req.Tag, req.Phylum, fromProtoOptions(req.Options)); err != nil {
+		return &grpcproto.InitResponse{Err: s.newError(err)}, nil
+	}
+	return &grpcproto.InitResponse{}, nil
+}
+
+func (s *grpcSubstrateServer) Call(ctx context.Context, req *grpcproto.CallRequest) (*grpcproto.CallResponse, error) {
+	resp, err := s.impl.CallCtx(ctx, req.Tag, req.Command, fromProtoOptions(req.Options))
+	if err != nil {
+		return &grpcproto.CallResponse{Err: s.newError(err)}, nil
+	}
+	return &grpcproto.CallResponse{Response: toProtoResponse(resp)}, nil
+}
+
+func (s *grpcSubstrateServer) QueryInfo(ctx context.Context, req *grpcproto.QueryInfoRequest) (*grpcproto.QueryInfoResponse, error) {
+	height, err := s.impl.QueryInfoCtx(ctx, req.Tag, fromProtoOptions(req.Options))
+	if err != nil {
+		return &grpcproto.QueryInfoResponse{Err: s.newError(err)}, nil
+	}
+	return &grpcproto.QueryInfoResponse{Height: height}, nil
+}
+
+func (s *grpcSubstrateServer) QueryBlock(ctx context.Context, req *grpcproto.QueryBlockRequest) (*grpcproto.QueryBlockResponse, error) {
+	block, err := s.impl.QueryBlockCtx(ctx, req.Tag, req.Height, fromProtoOptions(req.Options))
+	if err != nil {
+		return &grpcproto.QueryBlockResponse{Err: s.newError(err)}, nil
+	}
+	return &grpcproto.QueryBlockResponse{Block: toProtoBlock(block)}, nil
+}
+
+func (s *grpcSubstrateServer) HealthCheck(ctx context.Context, req *grpcproto.HealthCheckRequest) (*grpcproto.HealthCheckResponse, error) {
+	x, err := s.impl.HealthCheckCtx(ctx, int(req.X))
+	if err != nil {
+		return &grpcproto.HealthCheckResponse{Err: s.newError(err)}, nil
+	}
+	return &grpcproto.HealthCheckResponse{X: int32(x)}, nil
+}
+
+func (s *grpcSubstrateServer) SubscribeBlocks(req *grpcproto.SubscribeBlocksRequest, stream grpcproto.SubstrateService_SubscribeBlocksServer) error {
+	blocks, stop, err := s.impl.SubscribeBlocks(req.Tag, req.StartHeight, fromProtoOptions(req.Options))
+	if err != nil {
+		return stream.Send(&grpcproto.SubscribeBlocksResponse{Err: s.newError(err)})
+	}
+	defer stop()
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case block, ok := <-blocks:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(&grpcproto.SubscribeBlocksResponse{Block: toProtoBlock(block)}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *grpcSubstrateServer) Ping(ctx context.Context, req *grpcproto.PingRequest) (*grpcproto.PingResponse, error) {
+	if err := s.impl.PingCtx(ctx); err != nil {
+		return &grpcproto.PingResponse{Err: s.newError(err)}, nil
+	}
+	return &grpcproto.PingResponse{}, nil
+}
+
+func (s *grpcSubstrateServer) Resume(ctx context.Context, req *grpcproto.ResumeRequest) (*grpcproto.ResumeResponse, error) {
+	if err := s.impl.ResumeCtx(ctx, req.Tags); err != nil {
+		return &grpcproto.ResumeResponse{Err: s.newError(err)}, nil
+	}
+	return &grpcproto.ResumeResponse{}, nil
+}
+
+func (s *grpcSubstrateServer) ServeHTTP(ctx context.Context, req *grpcproto.ServeHTTPRequest) (*grpcproto.ServeHTTPResponse, error) {
+	resp, err := s.impl.ServeHTTP(req.Tag, &ConcreteHTTPRequest{
+		Method:  req.Method,
+		URL:     req.Url,
+		Header:  fromProtoHeader(req.Header),
+		Body:    req.Body,
+		Trailer: fromProtoHeader(req.Trailer),
+	})
+	if err != nil {
+		return &grpcproto.ServeHTTPResponse{Err: s.newError(err)}, nil
+	}
+	return &grpcproto.ServeHTTPResponse{
+		StatusCode: int32(resp.StatusCode),
+		Header:     toProtoHeader(resp.Header),
+		Body:       resp.Body,
+		Trailer:    toProtoHeader(resp.Trailer),
+	}, nil
+}
+
+// grpcSubstrateClient adapts grpcproto.SubstrateServiceClient to Substrate.
+type grpcSubstrateClient struct {
+	client grpcproto.SubstrateServiceClient
+}
+
+func (g *grpcSubstrateClient) NewRPC() (string, error) {
+	resp, err := g.client.NewRPC(context.Background(), &grpcproto.NewRPCRequest{})
+	if err != nil {
+		return "", err
+	}
+	if resp.Err != nil {
+		return "", fromProtoError(resp.Err)
+	}
+	return resp.Tag, nil
+}
+
+func (g *grpcSubstrateClient) CloseRPC(tag string) error {
+	return g.CloseRPCCtx(context.Background(), tag)
+}
+
+func (g *grpcSubstrateClient) CloseRPCCtx(ctx context.Context, tag string) error {
+	resp, err := g.client.CloseRPC(ctx, &grpcproto.CloseRPCRequest{Tag: tag})
+	if err != nil {
+		return err
+	}
+	if resp.Err != nil {
+		return fromProtoError(resp.Err)
+	}
+	return nil
+}
+
+func (g *grpcSubstrateClient) NewMockFrom(name string, version string, snapshot []byte) (string, error) {
+	resp, err := g.client.NewMockFrom(context.Background(), &grpcproto.NewMockFromRequest{Name: name, Version: version, Snapshot: snapshot})
+	if err != nil {
+		return "", err
+	}
+	if resp.Err != nil {
+		return "", fromProtoError(resp.Err)
+	}
+	return resp.Tag, nil
+}
+
+func (g *grpcSubstrateClient) SetCreatorWithAttributesMock(tag string, creator string, attrs map[string]string) error {
+	resp, err := g.client.SetCreatorWithAttributesMock(context.Background(), &grpcproto.SetCreatorWithAttributesMockRequest{Tag: tag, Creator: creator, Attrs: attrs})
+	if err != nil {
+		return err
+	}
+	if resp.Err != nil {
+		return fromProtoError(resp.Err)
+	}
+	return nil
+}
+
+func (g *grpcSubstrateClient) SnapshotMock(tag string) ([]byte, error) {
+	resp, err := g.client.SnapshotMock(context.Background(), &grpcproto.SnapshotMockRequest{Tag: tag})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Err != nil {
+		return nil, fromProtoError(resp.Err)
+	}
+	return resp.Snapshot, nil
+}
+
+func (g *grpcSubstrateClient) CloseMock(tag string) error {
+	return g.CloseMockCtx(context.Background(), tag)
+}
+
+func (g *grpcSubstrateClient) CloseMockCtx(ctx context.Context, tag string) error {
+	resp, err := g.client.CloseMock(ctx, &grpcproto.CloseMockRequest{Tag: tag})
+	if err != nil {
+		return err
+	}
+	if resp.Err != nil {
+		return fromProtoError(resp.Err)
+	}
+	return nil
+}
+
+func (g *grpcSubstrateClient) Init(tag string, phylum string, options *ConcreteRequestOptions) error {
+	return g.InitCtx(context.Background(), tag, phylum, options)
+}
+
+func (g *grpcSubstrateClient) InitCtx(ctx context.Context, tag string, phylum string, options *ConcreteRequestOptions) error {
+	resp, err := g.client.Init(ctx, &grpcproto.InitRequest{Tag: tag, Phylum: phylum, Options: toProtoOptions(options)})
+	if err != nil {
+		return err
+	}
+	if resp.Err != nil {
+		return fromProtoError(resp.Err)
+	}
+	return nil
+}
+
+func (g *grpcSubstrateClient) Call(tag string, command string, options *ConcreteRequestOptions) (*Response, error) {
+	return g.CallCtx(context.Background(), tag, command, options)
+}
+
+func (g *grpcSubstrateClient) CallCtx(ctx context.Context, tag string, command string, options *ConcreteRequestOptions) (*Response, error) {
+	resp, err := g.client.Call(ctx, &grpcproto.CallRequest{Tag: tag, Command: command, Options: toProtoOptions(options)})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Err != nil {
+		return nil, fromProtoError(resp.Err)
+	}
+	return fromProtoResponse(resp.Response), nil
+}
+
+func (g *grpcSubstrateClient) QueryInfo(tag string, options *ConcreteRequestOptions) (uint64, error) {
+	return g.QueryInfoCtx(context.Background(), tag, options)
+}
+
+func (g *grpcSubstrateClient) QueryInfoCtx(ctx context.Context, tag string, options *ConcreteRequestOptions) (uint64, error) {
+	resp, err := g.client.QueryInfo(ctx, &grpcproto.QueryInfoRequest{Tag: tag, Options: toProtoOptions(options)})
+	if err != nil {
+		return 0, err
+	}
+	if resp.Err != nil {
+		return 0, fromProtoError(resp.Err)
+	}
+	return resp.Height, nil
+}
+
+func (g *grpcSubstrateClient) QueryBlock(tag string, height uint64, options *ConcreteRequestOptions) (*Block, error) {
+	return g.QueryBlockCtx(context.Background(), tag, height, options)
+}
+
+func (g *grpcSubstrateClient) QueryBlockCtx(ctx context.Context, tag string, height uint64, options *ConcreteRequestOptions) (*Block, error) {
+	resp, err := g.client.QueryBlock(ctx, &grpcproto.QueryBlockRequest{Tag: tag, Height: height, Options: toProtoOptions(options)})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Err != nil {
+		return nil, fromProtoError(resp.Err)
+	}
+	return fromProtoBlock(resp.Block), nil
+}
+
+func (g *grpcSubstrateClient) HealthCheck(x int) (int, error) {
+	return g.HealthCheckCtx(context.Background(), x)
+}
+
+func (g *grpcSubstrateClient) HealthCheckCtx(ctx context.Context, x int) (int, error) {
+	resp, err := g.client.HealthCheck(ctx, &grpcproto.HealthCheckRequest{X: int32(x)})
+	if err != nil {
+		return 0, err
+	}
+	if resp.Err != nil {
+		return 0, fromProtoError(resp.Err)
+	}
+	return int(resp.X), nil
+}
+
+func (g *grpcSubstrateClient) Ping() error {
+	return g.PingCtx(context.Background())
+}
+
+func (g *grpcSubstrateClient) PingCtx(ctx context.Context) error {
+	resp, err := g.client.Ping(ctx, &grpcproto.PingRequest{})
+	if err != nil {
+		return err
+	}
+	if resp.Err != nil {
+		return fromProtoError(resp.Err)
+	}
+	return nil
+}
+
+func (g *grpcSubstrateClient) Resume(tags []string) error {
+	return g.ResumeCtx(context.Background(), tags)
+}
+
+func (g *grpcSubstrateClient) ResumeCtx(ctx context.Context, tags []string) error {
+	resp, err := g.client.Resume(ctx, &grpcproto.ResumeRequest{Tags: tags})
+	if err != nil {
+		return err
+	}
+	if resp.Err != nil {
+		return fromProtoError(resp.Err)
+	}
+	return nil
+}
+
+func (g *grpcSubstrateClient) ServeHTTP(tag string, req *ConcreteHTTPRequest) (*ConcreteHTTPResponse, error) {
+	resp, err := g.client.ServeHTTP(context.Background(), &grpcproto.ServeHTTPRequest{
+		Tag:     tag,
+		Method:  req.Method,
+		Url:     req.URL,
+		Header:  toProtoHeader(req.Header),
+		Body:    req.Body,
+		Trailer: toProtoHeader(req.Trailer),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Err != nil {
+		return nil, fromProtoError(resp.Err)
+	}
+	return &ConcreteHTTPResponse{
+		StatusCode: int(resp.StatusCode),
+		Header:     fromProtoHeader(resp.Header),
+		Body:       resp.Body,
+		Trailer:    fromProtoHeader(resp.Trailer),
+	}, nil
+}
+
+// SubscribeBlocks streams blocks from the server via a gRPC server-stream,
+// translating it into the channel/stop-func shape the Substrate interface
+// exposes. The returned stop func cancels the stream's context; the reader
+// goroutine then closes the channel once Recv unblocks with that
+// cancellation error.
+func (g *grpcSubstrateClient) SubscribeBlocks(tag string, startHeight uint64, options *ConcreteRequestOptions) (<-chan *Block, func() error, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := g.client.SubscribeBlocks(ctx, &grpcproto.SubscribeBlocksRequest{Tag: tag, StartHeight: startHeight, Options: toProtoOptions(options)})
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+
+	ch := make(chan *Block)
+	go func() {
+		defer close(ch)
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			if resp.Err != nil {
+				return
+			}
+			select {
+			case ch <- fromProtoBlock(resp.Block):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var cancelOnce sync.Once
+	stop := func() error {
+		cancelOnce.Do(cancel)
+		return nil
+	}
+	return ch, stop, nil
+}
+
+// IsTimeoutError checks if the error is a timeout error. This is done locally.
+func (g *grpcSubstrateClient) IsTimeoutError(err error) bool {
+	if e, ok := err.(Error); ok {
+		return e.IsTimeoutError
+	}
+	return false
+}
+
+func toProtoError(e *Error) *grpcproto.Error {
+	if e == nil {
+		return nil
+	}
+	return &grpcproto.Error{IsTimeoutError: e.IsTimeoutError, Diagnostic: e.Diagnostic}
+}
+
+func fromProtoError(e *grpcproto.Error) *Error {
+	if e == nil {
+		return nil
+	}
+	return &Error{IsTimeoutError: e.IsTimeoutError, Diagnostic: e.Diagnostic}
+}
+
+func toProtoOptions(o *ConcreteRequestOptions) *grpcproto.ConcreteRequestOptions {
+	if o == nil {
+		return nil
+	}
+	return &grpcproto.ConcreteRequestOptions{
+		Headers:             o.Headers,
+		Endpoint:            o.Endpoint,
+		Id:                  o.ID,
+		AuthToken:           o.AuthToken,
+		Params:              o.Params,
+		Transient:           o.Transient,
+		Timestamp:           o.Timestamp,
+		MspFilter:           o.MSPFilter,
+		MinEndorsers:        int32(o.MinEndorsers),
+		Creator:             o.Creator,
+		DependentTxId:       o.DependentTxID,
+		DisableWritePolling: o.DisableWritePolling,
+		CcFetchUrlDowngrade: o.CCFetchURLDowngrade,
+		CcFetchUrlProxy:     o.CCFetchURLProxy,
+	}
+}
+
+func fromProtoOptions(o *grpcproto.ConcreteRequestOptions) *ConcreteRequestOptions {
+	if o == nil {
+		return nil
+	}
+	return &ConcreteRequestOptions{
+		Headers:             o.Headers,
+		Endpoint:            o.Endpoint,
+		ID:                  o.Id,
+		AuthToken:           o.AuthToken,
+		Params:              o.Params,
+		Transient:           o.Transient,
+		Timestamp:           o.Timestamp,
+		MSPFilter:           o.MspFilter,
+		MinEndorsers:        int(o.MinEndorsers),
+		Creator:             o.Creator,
+		DependentTxID:       o.DependentTxId,
+		DisableWritePolling: o.DisableWritePolling,
+		CCFetchURLDowngrade: o.CcFetchUrlDowngrade,
+		CCFetchURLProxy:     o.CcFetchUrlProxy,
+	}
+}
+
+func toProtoResponse(r *Response) *grpcproto.Response {
+	if r == nil {
+		return nil
+	}
+	return &grpcproto.Response{
+		ResultJson:    r.ResultJSON,
+		HasError:      r.HasError,
+		ErrorCode:     int32(r.ErrorCode),
+		ErrorMessage:  r.ErrorMessage,
+		ErrorJson:     r.ErrorJSON,
+		TransactionId: r.TransactionID,
+	}
+}
+
+func fromProtoResponse(r *grpcproto.Response) *Response {
+	if r == nil {
+		return nil
+	}
+	return &Response{
+		ResultJSON:    r.ResultJson,
+		HasError:      r.HasError,
+		ErrorCode:     int(r.ErrorCode),
+		ErrorMessage:  r.ErrorMessage,
+		ErrorJSON:     r.ErrorJson,
+		TransactionID: r.TransactionId,
+	}
+}
+
+func toProtoHeader(h http.Header) map[string]*grpcproto.HeaderValues {
+	if h == nil {
+		return nil
+	}
+	out := make(map[string]*grpcproto.HeaderValues, len(h))
+	for k, vs := range h {
+		out[k] = &grpcproto.HeaderValues{Values: vs}
+	}
+	return out
+}
+
+func fromProtoHeader(h map[string]*grpcproto.HeaderValues) http.Header {
+	if h == nil {
+		return nil
+	}
+	out := make(http.Header, len(h))
+	for k, vs := range h {
+		out[k] = vs.Values
+	}
+	return out
+}
+
+func toProtoBlock(b *Block) *grpcproto.Block {
+	if b == nil {
+		return nil
+	}
+	txs := make([]*grpcproto.Transaction, len(b.Transactions))
+	for i, tx := range b.Transactions {
+		txs[i] = &grpcproto.Transaction{Id: tx.ID, Reason: tx.Reason, Event: tx.Event, ChaincodeId: tx.ChaincodeID}
+	}
+	return &grpcproto.Block{Hash: b.Hash, Transactions: txs}
+}
+
+func fromProtoBlock(b *grpcproto.Block) *Block {
+	if b == nil {
+		return nil
+	}
+	txs := make([]*Transaction, len(b.Transactions))
+	for i, tx := range b.Transactions {
+		txs[i] = &Transaction{ID: tx.Id, Reason: tx.Reason, Event: tx.Event, ChaincodeID: tx.ChaincodeId}
+	}
+	return &Block{Hash: b.Hash, Transactions: txs}
+}
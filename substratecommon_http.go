@@ -0,0 +1,126 @@
+package substratecommon
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// MaxHTTPBodyBytes caps the size of a request or response body forwarded
+// through Substrate.ServeHTTP. Bodies are read fully into memory on both
+// sides of the plugin boundary, so there's no true streaming; larger or
+// chunked payloads should be proxied some other way.
+const MaxHTTPBodyBytes = 4 << 20 // 4MiB
+
+// ConcreteHTTPRequest is a flattened, wire-safe form of http.Request,
+// analogous to ConcreteRequestOptions. URL is the request's RequestURI
+// (path plus query string), not an absolute URL.
+type ConcreteHTTPRequest struct {
+	Method  string
+	URL     string
+	Header  http.Header
+	Body    []byte
+	Trailer http.Header
+}
+
+// ConcreteHTTPResponse is a flattened, wire-safe form of http.Response.
+type ConcreteHTTPResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	Trailer    http.Header
+}
+
+// SubstrateHTTPHandler is an http.Handler that forwards every request it
+// receives to the named RPC/mock instance's Substrate.ServeHTTP, writing
+// back whatever ConcreteHTTPResponse comes back. It's the host-side half of
+// the ServeHTTP passthrough; WrapHTTPHandler is the plugin-side half.
+type SubstrateHTTPHandler struct {
+	Substrate Substrate
+	Tag       string
+}
+
+// ServeHTTP implements http.Handler.
+func (h *SubstrateHTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req, err := flattenHTTPRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp, err := h.Substrate.ServeHTTP(h.Tag, req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	for k, vs := range resp.Header {
+		for _, v := range vs {
+			w.Header().Add(k, v)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+	w.Write(resp.Body) //nolint:errcheck
+}
+
+func flattenHTTPRequest(r *http.Request) (*ConcreteHTTPRequest, error) {
+	if r.ContentLength < 0 {
+		return nil, fmt.Errorf("substratecommon: chunked request bodies are not supported by Substrate.ServeHTTP")
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, MaxHTTPBodyBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("substratecommon: reading request body: %w", err)
+	}
+	if int64(len(body)) > MaxHTTPBodyBytes {
+		return nil, fmt.Errorf("substratecommon: request body exceeds %d bytes", MaxHTTPBodyBytes)
+	}
+	return &ConcreteHTTPRequest{
+		Method:  r.Method,
+		URL:     r.URL.RequestURI(),
+		Header:  r.Header,
+		Body:    body,
+		Trailer: r.Trailer,
+	}, nil
+}
+
+// WrapHTTPHandler adapts a standard http.Handler into the function signature
+// a plugin's Substrate.ServeHTTP implementation needs: it rebuilds an
+// *http.Request from the flattened ConcreteHTTPRequest, runs it through
+// handler, and flattens the recorded response back down.
+func WrapHTTPHandler(handler http.Handler) func(tag string, req *ConcreteHTTPRequest) (*ConcreteHTTPResponse, error) {
+	return func(tag string, req *ConcreteHTTPRequest) (*ConcreteHTTPResponse, error) {
+		httpReq, err := http.NewRequest(req.Method, req.URL, bytes.NewReader(req.Body))
+		if err != nil {
+			return nil, fmt.Errorf("substratecommon: rebuilding request: %w", err)
+		}
+		httpReq.Header = req.Header
+		httpReq.Trailer = req.Trailer
+
+		rec := newHTTPResponseRecorder()
+		handler.ServeHTTP(rec, httpReq)
+
+		return &ConcreteHTTPResponse{
+			StatusCode: rec.statusCode,
+			Header:     rec.header,
+			Body:       rec.body.Bytes(),
+		}, nil
+	}
+}
+
+// httpResponseRecorder is a minimal http.ResponseWriter that captures a
+// handler's output in memory, so WrapHTTPHandler can flatten it into a
+// ConcreteHTTPResponse without depending on net/http/httptest.
+type httpResponseRecorder struct {
+	header     http.Header
+	body       bytes.Buffer
+	statusCode int
+}
+
+func newHTTPResponseRecorder() *httpResponseRecorder {
+	return &httpResponseRecorder{header: http.Header{}, statusCode: http.StatusOK}
+}
+
+func (r *httpResponseRecorder) Header() http.Header { return r.header }
+
+func (r *httpResponseRecorder) Write(b []byte) (int, error) { return r.body.Write(b) }
+
+func (r *httpResponseRecorder) WriteHeader(statusCode int) { r.statusCode = statusCode }
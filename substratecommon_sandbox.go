@@ -0,0 +1,60 @@
+package substratecommon
+
+import (
+	"os/exec"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// SandboxConfig describes the OS-level isolation ConnectWithSandbox applies
+// to the plugin subprocess. Phylum code is customer-authored and by default
+// runs with the full ambient privileges of the host process; SandboxConfig
+// narrows that down to roughly what substrate/shiroclient actually need.
+//
+// The enforcement is best-effort and OS-specific: see
+// substratecommon_sandbox_linux.go for what's actually applied on Linux, and
+// substratecommon_sandbox_other.go for the no-op fallback elsewhere.
+type SandboxConfig struct {
+	// AllowNetwork lists the "host:port" destinations the plugin is intended
+	// to be restricted to dialing. This is currently advisory only: the
+	// Linux implementation does not yet set up the network namespace and
+	// nftables rule (or SOCKS shim) needed to enforce it, and logs a
+	// warning to that effect when set. Don't rely on it to deny outbound
+	// network access.
+	AllowNetwork []string
+
+	// ScratchDir is intended to be the one directory the plugin may write
+	// to. This is currently advisory only: the Linux implementation does
+	// not yet set up the mount namespace/bind-mount needed to make the rest
+	// of the filesystem read-only, and logs a warning to that effect when
+	// set. Don't rely on it to make the rest of disk read-only.
+	ScratchDir string
+
+	// MemoryLimitBytes caps the plugin's address space (RLIMIT_AS). Zero
+	// means no limit.
+	MemoryLimitBytes int64
+
+	// ExtraSyscalls lists additional syscall names (as in their C library
+	// names, e.g. "openat") to allow beyond the built-in baseline, for
+	// phyla that legitimately need more than the default filter permits.
+	ExtraSyscalls []string
+}
+
+// ConnectWithSandbox runs the plugin subprocess inside an OS-level sandbox
+// built from cfg instead of with the host process's ambient privileges. See
+// SandboxConfig for what it restricts and substratecommon_sandbox_linux.go
+// for the Linux implementation; other OSes log a warning and run
+// unsandboxed.
+func ConnectWithSandbox(cfg SandboxConfig) ConnectOption {
+	return func(co *connectOption) error {
+		co.sandbox = &cfg
+		return nil
+	}
+}
+
+// applySandbox configures cmd so that, once started, the plugin process runs
+// under the restrictions described by cfg. It must be called before
+// cmd.Start.
+func applySandbox(cmd *exec.Cmd, cfg *SandboxConfig, logger hclog.Logger) error {
+	return applySandboxOS(cmd, cfg, logger)
+}
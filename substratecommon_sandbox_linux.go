@@ -0,0 +1,296 @@
+//go:build linux
+// +build linux
+
+package substratecommon
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+	"unsafe"
+
+	"github.com/hashicorp/go-hclog"
+	"golang.org/x/sys/unix"
+)
+
+// sandboxReexecEnv, when set to "1" in a process's environment, tells this
+// package's init function that the process was re-executed by applySandbox
+// specifically to apply sandboxing to itself before execve-ing into the real
+// plugin command. There's no other way to run code between fork and exec
+// from net/os.exec, so the sandboxed process re-execs /proc/self/exe, reads
+// its restrictions back out of sandboxConfigEnv, applies them, then execve's
+// the original command. See applySandboxOS.
+const sandboxReexecEnv = "_SUBSTRATECOMMON_SANDBOX_REEXEC"
+
+// sandboxConfigEnv carries the JSON-encoded sandboxPayload across the
+// re-exec in sandboxReexecEnv above.
+const sandboxConfigEnv = "_SUBSTRATECOMMON_SANDBOX_CONFIG"
+
+// sandboxPayload is the wire form of the parts of SandboxConfig the re-exec
+// step needs, plus the real command it should ultimately run.
+type sandboxPayload struct {
+	Argv             []string
+	AllowNetwork     []string
+	ScratchDir       string
+	MemoryLimitBytes int64
+	ExtraSyscalls    []string
+}
+
+func init() {
+	if os.Getenv(sandboxReexecEnv) != "1" {
+		return
+	}
+	// This process is the re-exec'd sandbox init step; it never returns.
+	err := sandboxInit()
+	if err == nil {
+		err = fmt.Errorf("substratecommon: sandbox init: execve returned with no error")
+	}
+	fmt.Fprintf(os.Stderr, "substratecommon: sandbox init failed: %v\n", err)
+	os.Exit(127)
+}
+
+// sandboxInit applies the restrictions described by sandboxConfigEnv to the
+// current process, then execve's into the real plugin command. On success it
+// never returns.
+func sandboxInit() error {
+	var payload sandboxPayload
+	if err := json.Unmarshal([]byte(os.Getenv(sandboxConfigEnv)), &payload); err != nil {
+		return fmt.Errorf("decoding sandbox config: %w", err)
+	}
+
+	if payload.MemoryLimitBytes > 0 {
+		limit := uint64(payload.MemoryLimitBytes)
+		rlimit := unix.Rlimit{Cur: limit, Max: limit}
+		if err := unix.Setrlimit(unix.RLIMIT_AS, &rlimit); err != nil {
+			return fmt.Errorf("setting RLIMIT_AS: %w", err)
+		}
+	}
+
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("setting no_new_privs: %w", err)
+	}
+
+	filter, err := buildSeccompFilter(payload.ExtraSyscalls)
+	if err != nil {
+		return fmt.Errorf("building seccomp filter: %w", err)
+	}
+	if err := installSeccompFilter(filter); err != nil {
+		return fmt.Errorf("installing seccomp filter: %w", err)
+	}
+
+	env := os.Environ()
+	return syscall.Exec(payload.Argv[0], payload.Argv, env)
+}
+
+// applySandboxOS rewrites cmd so that, once started, it actually launches
+// this same binary with sandboxReexecEnv set; init (above) catches that in
+// the child right after fork, applies the sandbox, and only then execve's
+// the plugin command cmd originally described. AllowNetwork and ScratchDir
+// are not enforced by the syscall filter built here: narrowing the plugin to
+// a single writable directory needs a mount namespace/bind-mount the caller
+// must set up (ScratchDir is still passed through and reserved for that),
+// and restricting destinations needs a network namespace plus an nftables
+// rule or a SOCKS shim in front of the plugin's outbound connections. Both
+// are left as a TODO for a follow-up; see SandboxConfig's doc comment.
+func applySandboxOS(cmd *exec.Cmd, cfg *SandboxConfig, logger hclog.Logger) error {
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("resolving re-exec target: %w", err)
+	}
+
+	argv := append([]string{cmd.Path}, cmd.Args[1:]...)
+	payload := sandboxPayload{
+		Argv:             argv,
+		AllowNetwork:     cfg.AllowNetwork,
+		ScratchDir:       cfg.ScratchDir,
+		MemoryLimitBytes: cfg.MemoryLimitBytes,
+		ExtraSyscalls:    cfg.ExtraSyscalls,
+	}
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encoding sandbox config: %w", err)
+	}
+
+	if len(cfg.AllowNetwork) > 0 {
+		logger.Warn("ConnectWithSandbox: AllowNetwork is not yet enforced; it requires a network namespace and nftables rule (or SOCKS shim) this package doesn't set up", "allow_network", cfg.AllowNetwork)
+	}
+	if cfg.ScratchDir != "" {
+		logger.Warn("ConnectWithSandbox: ScratchDir is not yet enforced; it requires a mount namespace this package doesn't set up", "scratch_dir", cfg.ScratchDir)
+	}
+
+	cmd.Path = self
+	cmd.Args = append([]string{self}, cmd.Args...)
+	cmd.Env = append(appendedEnv(cmd.Env), sandboxReexecEnv+"=1", sandboxConfigEnv+"="+string(encoded))
+	return nil
+}
+
+func appendedEnv(env []string) []string {
+	if env != nil {
+		return env
+	}
+	return os.Environ()
+}
+
+// baseSyscalls is the minimal set of syscalls a net/rpc or gRPC plugin
+// dialed over a unix socket / inherited stdio pipe needs: process
+// lifecycle, memory management, and blocking I/O on file descriptors it
+// already holds, plus execve. execve has to stay allowed: sandboxInit
+// installs this same filter on itself and then execve's into the real
+// plugin binary to hand off, so excluding it makes every sandboxed launch
+// fail with EPERM. The tradeoff is that the plugin process, once running
+// under this filter, could also call execve again; baseSyscalls still
+// excludes ptrace, so it can't trace or modify the process that execve
+// would replace it with, but this is not a one-shot guarantee. See
+// SandboxConfig's doc comment for the other restrictions that are
+// similarly best-effort rather than airtight. clone3 is included alongside
+// clone because the Go runtime a plugin binary is built with may use
+// either to create OS threads depending on toolchain/kernel; without it
+// the plugin crashes at startup the moment it spawns its second thread.
+// arch_prctl is needed for the same reason: the runtime uses it to set
+// each new OS thread's FS base (thread-local storage) and deliberately
+// crashes if the call is denied rather than erroring cleanly.
+var baseSyscalls = []string{
+	"read", "write", "readv", "writev", "close", "fstat", "lseek", "execve",
+	"mmap", "munmap", "mprotect", "brk", "madvise", "arch_prctl",
+	"rt_sigaction", "rt_sigprocmask", "rt_sigreturn", "sigaltstack",
+	"futex", "sched_yield", "sched_getaffinity", "nanosleep", "clock_gettime", "clock_nanosleep", "clock_getres",
+	"epoll_create1", "epoll_ctl", "epoll_wait", "epoll_pwait", "poll", "ppoll", "pselect6", "select",
+	"socket", "connect", "accept4", "bind", "listen", "setsockopt", "getsockopt", "getsockname", "getpeername",
+	"sendto", "recvfrom", "sendmsg", "recvmsg", "shutdown",
+	"openat", "open", "pipe2", "pipe", "dup", "dup2", "dup3", "fcntl", "ioctl",
+	"getrandom", "getpid", "gettid", "getuid", "geteuid", "getgid", "getegid",
+	"clone", "clone3", "wait4", "exit", "exit_group", "restart_syscall",
+	"prctl", "set_robust_list", "sigaltstack", "rseq", "tgkill",
+}
+
+// buildSeccompFilter compiles a classic-BPF program that allows
+// baseSyscalls plus extra, and returns SECCOMP_RET_ERRNO(EPERM) for
+// everything else, including any syscall made under a foreign instruction
+// set (e.g. a 32-bit compat syscall on an amd64 host).
+func buildSeccompFilter(extra []string) ([]unix.SockFilter, error) {
+	names := make([]string, 0, len(baseSyscalls)+len(extra))
+	names = append(names, baseSyscalls...)
+	names = append(names, extra...)
+
+	nums := make([]uint32, 0, len(names))
+	seen := make(map[uint32]bool, len(names))
+	for _, name := range names {
+		nr, ok := syscallNumbers[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown syscall %q", name)
+		}
+		if !seen[nr] {
+			seen[nr] = true
+			nums = append(nums, nr)
+		}
+	}
+
+	prog := []unix.SockFilter{
+		// Validate the syscall was made in the expected ABI; otherwise a
+		// 32-bit compat syscall could reach a number this filter never
+		// checked.
+		bpfStmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, seccompDataArchOffset),
+		bpfJump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, auditArchNative, 1, 0),
+		bpfStmt(unix.BPF_RET|unix.BPF_K, seccompRetKillProcess),
+		bpfStmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, seccompDataNrOffset),
+	}
+	for _, nr := range nums {
+		prog = append(prog, bpfJump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, nr, 0, 1))
+		prog = append(prog, bpfStmt(unix.BPF_RET|unix.BPF_K, seccompRetAllow))
+	}
+	prog = append(prog, bpfStmt(unix.BPF_RET|unix.BPF_K, seccompRetErrnoEPerm))
+	return prog, nil
+}
+
+// installSeccompFilter loads prog via prctl(PR_SET_SECCOMP), which requires
+// PR_SET_NO_NEW_PRIVS to already be set (see sandboxInit).
+func installSeccompFilter(prog []unix.SockFilter) error {
+	fprog := unix.SockFprog{
+		Len:    uint16(len(prog)),
+		Filter: &prog[0],
+	}
+	return unix.Prctl(unix.PR_SET_SECCOMP, uintptr(unix.SECCOMP_MODE_FILTER), uintptr(unsafe.Pointer(&fprog)), 0, 0)
+}
+
+func bpfStmt(code uint16, k uint32) unix.SockFilter {
+	return unix.SockFilter{Code: code, K: k}
+}
+
+func bpfJump(code uint16, k uint32, jt, jf uint8) unix.SockFilter {
+	return unix.SockFilter{Code: code, Jt: jt, Jf: jf, K: k}
+}
+
+// The offsets and constants below mirror <linux/seccomp.h>/<linux/filter.h>
+// and <linux/audit.h>; x/sys/unix doesn't expose them.
+const (
+	// struct seccomp_data { int nr; __u32 arch; __u64 instruction_pointer; __u64 args[6]; }
+	seccompDataNrOffset   = 0
+	seccompDataArchOffset = 4
+
+	seccompRetKillProcess = 0x80000000
+	seccompRetErrnoEPerm  = 0x00050000 | uint32(unix.EPERM)
+	seccompRetAllow       = 0x7fff0000
+
+	auditArchX86_64  = 0xc000003e
+	auditArchAARCH64 = 0xc00000b7
+)
+
+var syscallNumbers = buildSyscallNumberTable()
+
+func buildSyscallNumberTable() map[string]uint32 {
+	return map[string]uint32{
+		"read": uint32(unix.SYS_READ), "write": uint32(unix.SYS_WRITE),
+		"readv": uint32(unix.SYS_READV), "writev": uint32(unix.SYS_WRITEV),
+		"execve": uint32(unix.SYS_EXECVE),
+		"close":  uint32(unix.SYS_CLOSE), "fstat": uint32(unix.SYS_FSTAT),
+		"lseek": uint32(unix.SYS_LSEEK), "mmap": uint32(unix.SYS_MMAP),
+		"munmap": uint32(unix.SYS_MUNMAP), "mprotect": uint32(unix.SYS_MPROTECT),
+		"arch_prctl": uint32(unix.SYS_ARCH_PRCTL),
+		"brk":        uint32(unix.SYS_BRK), "madvise": uint32(unix.SYS_MADVISE),
+		"rt_sigaction": uint32(unix.SYS_RT_SIGACTION), "rt_sigprocmask": uint32(unix.SYS_RT_SIGPROCMASK),
+		"rt_sigreturn": uint32(unix.SYS_RT_SIGRETURN), "sigaltstack": uint32(unix.SYS_SIGALTSTACK),
+		"futex": uint32(unix.SYS_FUTEX), "sched_yield": uint32(unix.SYS_SCHED_YIELD),
+		"sched_getaffinity": uint32(unix.SYS_SCHED_GETAFFINITY), "nanosleep": uint32(unix.SYS_NANOSLEEP),
+		"clock_gettime": uint32(unix.SYS_CLOCK_GETTIME), "clock_nanosleep": uint32(unix.SYS_CLOCK_NANOSLEEP),
+		"clock_getres":  uint32(unix.SYS_CLOCK_GETRES),
+		"epoll_create1": uint32(unix.SYS_EPOLL_CREATE1), "epoll_ctl": uint32(unix.SYS_EPOLL_CTL),
+		"epoll_wait": uint32(unix.SYS_EPOLL_WAIT), "epoll_pwait": uint32(unix.SYS_EPOLL_PWAIT),
+		"poll": uint32(unix.SYS_POLL), "ppoll": uint32(unix.SYS_PPOLL),
+		"pselect6": uint32(unix.SYS_PSELECT6), "select": uint32(unix.SYS_SELECT),
+		"socket": uint32(unix.SYS_SOCKET), "connect": uint32(unix.SYS_CONNECT),
+		"accept4": uint32(unix.SYS_ACCEPT4), "bind": uint32(unix.SYS_BIND), "listen": uint32(unix.SYS_LISTEN),
+		"setsockopt": uint32(unix.SYS_SETSOCKOPT), "getsockopt": uint32(unix.SYS_GETSOCKOPT),
+		"getsockname": uint32(unix.SYS_GETSOCKNAME), "getpeername": uint32(unix.SYS_GETPEERNAME),
+		"sendto": uint32(unix.SYS_SENDTO), "recvfrom": uint32(unix.SYS_RECVFROM),
+		"sendmsg": uint32(unix.SYS_SENDMSG), "recvmsg": uint32(unix.SYS_RECVMSG), "shutdown": uint32(unix.SYS_SHUTDOWN),
+		"openat": uint32(unix.SYS_OPENAT), "open": uint32(unix.SYS_OPEN),
+		"pipe2": uint32(unix.SYS_PIPE2), "pipe": uint32(unix.SYS_PIPE),
+		"dup": uint32(unix.SYS_DUP), "dup2": uint32(unix.SYS_DUP2), "dup3": uint32(unix.SYS_DUP3),
+		"fcntl": uint32(unix.SYS_FCNTL), "ioctl": uint32(unix.SYS_IOCTL),
+		"getrandom": uint32(unix.SYS_GETRANDOM), "getpid": uint32(unix.SYS_GETPID), "gettid": uint32(unix.SYS_GETTID),
+		"getuid": uint32(unix.SYS_GETUID), "geteuid": uint32(unix.SYS_GETEUID),
+		"getgid": uint32(unix.SYS_GETGID), "getegid": uint32(unix.SYS_GETEGID),
+		"clone": uint32(unix.SYS_CLONE), "clone3": uint32(unix.SYS_CLONE3), "wait4": uint32(unix.SYS_WAIT4),
+		"exit": uint32(unix.SYS_EXIT), "exit_group": uint32(unix.SYS_EXIT_GROUP),
+		"restart_syscall": uint32(unix.SYS_RESTART_SYSCALL), "prctl": uint32(unix.SYS_PRCTL),
+		"set_robust_list": uint32(unix.SYS_SET_ROBUST_LIST), "rseq": uint32(unix.SYS_RSEQ),
+		"tgkill": uint32(unix.SYS_TGKILL),
+	}
+}
+
+// auditArchNative is the AUDIT_ARCH_* value for the architecture this
+// package was built for, i.e. the one the kernel will report in
+// seccomp_data.arch for syscalls this process actually makes.
+var auditArchNative = nativeAuditArch()
+
+func nativeAuditArch() uint32 {
+	switch runtime.GOARCH {
+	case "arm64":
+		return auditArchAARCH64
+	default:
+		return auditArchX86_64
+	}
+}
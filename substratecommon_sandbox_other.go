@@ -0,0 +1,19 @@
+//go:build !linux
+// +build !linux
+
+package substratecommon
+
+import (
+	"os/exec"
+	"runtime"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// applySandboxOS is a no-op on non-Linux platforms: seccomp-bpf is a Linux
+// facility, so there's nothing here to enforce cfg with. The plugin still
+// runs, just unsandboxed.
+func applySandboxOS(cmd *exec.Cmd, cfg *SandboxConfig, logger hclog.Logger) error {
+	logger.Warn("ConnectWithSandbox has no effect on this OS; the plugin will run unsandboxed", "goos", runtime.GOOS)
+	return nil
+}
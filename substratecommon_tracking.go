@@ -0,0 +1,171 @@
+package substratecommon
+
+import (
+	"context"
+	"sync"
+)
+
+// trackingSubstrate wraps a Substrate implementation and records which
+// RPC/mock instance tags are currently active, so SubstrateConnection's
+// supervisor can pass them to Resume after respawning a crashed plugin. It
+// also lets GetSubstrate hand out one stable value that keeps working
+// across a restart, by swapping the underlying Substrate in place.
+type trackingSubstrate struct {
+	mu      sync.Mutex
+	current Substrate
+	tags    map[string]struct{}
+}
+
+func newTrackingSubstrate(underlying Substrate) *trackingSubstrate {
+	return &trackingSubstrate{current: underlying, tags: map[string]struct{}{}}
+}
+
+func (t *trackingSubstrate) get() Substrate {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.current
+}
+
+func (t *trackingSubstrate) swap(underlying Substrate) {
+	t.mu.Lock()
+	t.current = underlying
+	t.mu.Unlock()
+}
+
+func (t *trackingSubstrate) addTag(tag string) {
+	t.mu.Lock()
+	t.tags[tag] = struct{}{}
+	t.mu.Unlock()
+}
+
+func (t *trackingSubstrate) removeTag(tag string) {
+	t.mu.Lock()
+	delete(t.tags, tag)
+	t.mu.Unlock()
+}
+
+func (t *trackingSubstrate) tagList() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	tags := make([]string, 0, len(t.tags))
+	for tag := range t.tags {
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+func (t *trackingSubstrate) NewRPC() (string, error) {
+	tag, err := t.get().NewRPC()
+	if err == nil {
+		t.addTag(tag)
+	}
+	return tag, err
+}
+
+func (t *trackingSubstrate) CloseRPC(tag string) error {
+	err := t.get().CloseRPC(tag)
+	t.removeTag(tag)
+	return err
+}
+
+func (t *trackingSubstrate) CloseRPCCtx(ctx context.Context, tag string) error {
+	err := t.get().CloseRPCCtx(ctx, tag)
+	t.removeTag(tag)
+	return err
+}
+
+func (t *trackingSubstrate) NewMockFrom(name string, version string, snapshot []byte) (string, error) {
+	tag, err := t.get().NewMockFrom(name, version, snapshot)
+	if err == nil {
+		t.addTag(tag)
+	}
+	return tag, err
+}
+
+func (t *trackingSubstrate) SetCreatorWithAttributesMock(tag string, creator string, attrs map[string]string) error {
+	return t.get().SetCreatorWithAttributesMock(tag, creator, attrs)
+}
+
+func (t *trackingSubstrate) SnapshotMock(tag string) ([]byte, error) {
+	return t.get().SnapshotMock(tag)
+}
+
+func (t *trackingSubstrate) CloseMock(tag string) error {
+	err := t.get().CloseMock(tag)
+	t.removeTag(tag)
+	return err
+}
+
+func (t *trackingSubstrate) CloseMockCtx(ctx context.Context, tag string) error {
+	err := t.get().CloseMockCtx(ctx, tag)
+	t.removeTag(tag)
+	return err
+}
+
+func (t *trackingSubstrate) Init(tag string, phylum string, options *ConcreteRequestOptions) error {
+	return t.get().Init(tag, phylum, options)
+}
+
+func (t *trackingSubstrate) InitCtx(ctx context.Context, tag string, phylum string, options *ConcreteRequestOptions) error {
+	return t.get().InitCtx(ctx, tag, phylum, options)
+}
+
+func (t *trackingSubstrate) Call(tag string, command string, options *ConcreteRequestOptions) (*Response, error) {
+	return t.get().Call(tag, command, options)
+}
+
+func (t *trackingSubstrate) CallCtx(ctx context.Context, tag string, command string, options *ConcreteRequestOptions) (*Response, error) {
+	return t.get().CallCtx(ctx, tag, command, options)
+}
+
+func (t *trackingSubstrate) QueryInfo(tag string, options *ConcreteRequestOptions) (uint64, error) {
+	return t.get().QueryInfo(tag, options)
+}
+
+func (t *trackingSubstrate) QueryInfoCtx(ctx context.Context, tag string, options *ConcreteRequestOptions) (uint64, error) {
+	return t.get().QueryInfoCtx(ctx, tag, options)
+}
+
+func (t *trackingSubstrate) QueryBlock(tag string, height uint64, options *ConcreteRequestOptions) (*Block, error) {
+	return t.get().QueryBlock(tag, height, options)
+}
+
+func (t *trackingSubstrate) QueryBlockCtx(ctx context.Context, tag string, height uint64, options *ConcreteRequestOptions) (*Block, error) {
+	return t.get().QueryBlockCtx(ctx, tag, height, options)
+}
+
+func (t *trackingSubstrate) HealthCheck(x int) (int, error) {
+	return t.get().HealthCheck(x)
+}
+
+func (t *trackingSubstrate) HealthCheckCtx(ctx context.Context, x int) (int, error) {
+	return t.get().HealthCheckCtx(ctx, x)
+}
+
+func (t *trackingSubstrate) SubscribeBlocks(tag string, startHeight uint64, options *ConcreteRequestOptions) (<-chan *Block, func() error, error) {
+	return t.get().SubscribeBlocks(tag, startHeight, options)
+}
+
+func (t *trackingSubstrate) Ping() error {
+	return t.get().Ping()
+}
+
+func (t *trackingSubstrate) PingCtx(ctx context.Context) error {
+	return t.get().PingCtx(ctx)
+}
+
+func (t *trackingSubstrate) Resume(tags []string) error {
+	return t.get().Resume(tags)
+}
+
+func (t *trackingSubstrate) ResumeCtx(ctx context.Context, tags []string) error {
+	return t.get().ResumeCtx(ctx, tags)
+}
+
+func (t *trackingSubstrate) ServeHTTP(tag string, req *ConcreteHTTPRequest) (*ConcreteHTTPResponse, error) {
+	return t.get().ServeHTTP(tag, req)
+}
+
+func (t *trackingSubstrate) IsTimeoutError(err error) bool {
+	return t.get().IsTimeoutError(err)
+}
@@ -0,0 +1,149 @@
+package substratewrapper
+
+import (
+	"context"
+
+	"github.com/luthersystems/substratecommon"
+)
+
+// Authorizer decides whether a method call is permitted, given the
+// configs that will be flattened into the request. Implementations
+// typically inspect claims carried on ctx.
+type Authorizer interface {
+	Authorize(ctx context.Context, method string, configs []substratecommon.Config) error
+}
+
+// CreatorAttributer is an optional interface an Authorizer can implement to
+// drive SetCreatorWithAttributes on a mock instance automatically, derived
+// from ctx, before every authorized call. This lets tests exercise
+// attribute-based access control deterministically without threading
+// creator setup through every test case. ok is false when ctx carries no
+// identity the Authorizer recognizes, in which case the creator is left
+// untouched.
+type CreatorAttributer interface {
+	CreatorAttributes(ctx context.Context) (creator string, attrs map[string]string, ok bool)
+}
+
+type substrateInstanceWrapperAuthorized struct {
+	underlying SubstrateInstanceWrapperCommon
+	authorizer Authorizer
+}
+
+// NewSubstrateInstanceWrapperAuthorized returns a decorator that runs
+// authorizer.Authorize before Init, Call, QueryInfo, and QueryBlock,
+// rejecting the call before it reaches underlying if authorization fails.
+func NewSubstrateInstanceWrapperAuthorized(underlying SubstrateInstanceWrapperCommon, authorizer Authorizer) SubstrateInstanceWrapperCommon {
+	return &substrateInstanceWrapperAuthorized{underlying: underlying, authorizer: authorizer}
+}
+
+// applyCreatorAttributes drives SetCreatorWithAttributes on the underlying
+// mock instance when both the authorizer and the underlying wrapper support
+// it. It is a no-op for the RPC path, where there is no mock creator to set.
+func (a *substrateInstanceWrapperAuthorized) applyCreatorAttributes(ctx context.Context) error {
+	ca, ok := a.authorizer.(CreatorAttributer)
+	if !ok {
+		return nil
+	}
+	mock, ok := a.underlying.(SubstrateInstanceWrapperMock)
+	if !ok {
+		return nil
+	}
+	creator, attrs, ok := ca.CreatorAttributes(ctx)
+	if !ok {
+		return nil
+	}
+	return mock.SetCreatorWithAttributes(creator, attrs)
+}
+
+func (a *substrateInstanceWrapperAuthorized) Close() error {
+	return a.underlying.Close()
+}
+
+func (a *substrateInstanceWrapperAuthorized) CloseCtx(ctx context.Context) error {
+	return a.underlying.CloseCtx(ctx)
+}
+
+func (a *substrateInstanceWrapperAuthorized) HealthCheck(x int) (int, error) {
+	return a.underlying.HealthCheck(x)
+}
+
+func (a *substrateInstanceWrapperAuthorized) HealthCheckCtx(ctx context.Context, x int) (int, error) {
+	return a.underlying.HealthCheckCtx(ctx, x)
+}
+
+func (a *substrateInstanceWrapperAuthorized) NewCoherent() SubstrateInstanceWrapperCommon {
+	return NewSubstrateInstanceWrapperCoherent(a)
+}
+
+func (a *substrateInstanceWrapperAuthorized) NewContextCoherent() SubstrateInstanceWrapperCommon {
+	return NewSubstrateInstanceWrapperContextCoherent(a)
+}
+
+func (a *substrateInstanceWrapperAuthorized) IsTimeoutError(err error) bool {
+	return a.underlying.IsTimeoutError(err)
+}
+
+func (a *substrateInstanceWrapperAuthorized) GetLastTransactionID() string {
+	return a.underlying.GetLastTransactionID()
+}
+
+func (a *substrateInstanceWrapperAuthorized) Upcast() *SubstrateInstanceWrapperCommon {
+	var common SubstrateInstanceWrapperCommon = a
+	return &common
+}
+
+func (a *substrateInstanceWrapperAuthorized) Init(phylum string, configs ...substratecommon.Config) error {
+	return a.InitCtx(flattenCtx(configs...), phylum, configs...)
+}
+
+func (a *substrateInstanceWrapperAuthorized) InitCtx(ctx context.Context, phylum string, configs ...substratecommon.Config) error {
+	if err := a.authorizer.Authorize(ctx, "Init", configs); err != nil {
+		return err
+	}
+	if err := a.applyCreatorAttributes(ctx); err != nil {
+		return err
+	}
+	return a.underlying.InitCtx(ctx, phylum, configs...)
+}
+
+func (a *substrateInstanceWrapperAuthorized) Call(method string, configs ...substratecommon.Config) (*substratecommon.Response, error) {
+	return a.CallCtx(flattenCtx(configs...), method, configs...)
+}
+
+func (a *substrateInstanceWrapperAuthorized) CallCtx(ctx context.Context, method string, configs ...substratecommon.Config) (*substratecommon.Response, error) {
+	if err := a.authorizer.Authorize(ctx, method, configs); err != nil {
+		return nil, err
+	}
+	if err := a.applyCreatorAttributes(ctx); err != nil {
+		return nil, err
+	}
+	return a.underlying.CallCtx(ctx, method, configs...)
+}
+
+func (a *substrateInstanceWrapperAuthorized) QueryInfo(configs ...substratecommon.Config) (uint64, error) {
+	return a.QueryInfoCtx(flattenCtx(configs...), configs...)
+}
+
+func (a *substrateInstanceWrapperAuthorized) QueryInfoCtx(ctx context.Context, configs ...substratecommon.Config) (uint64, error) {
+	if err := a.authorizer.Authorize(ctx, "QueryInfo", configs); err != nil {
+		return 0, err
+	}
+	if err := a.applyCreatorAttributes(ctx); err != nil {
+		return 0, err
+	}
+	return a.underlying.QueryInfoCtx(ctx, configs...)
+}
+
+func (a *substrateInstanceWrapperAuthorized) QueryBlock(blockNumber uint64, configs ...substratecommon.Config) (*substratecommon.Block, error) {
+	return a.QueryBlockCtx(flattenCtx(configs...), blockNumber, configs...)
+}
+
+func (a *substrateInstanceWrapperAuthorized) QueryBlockCtx(ctx context.Context, blockNumber uint64, configs ...substratecommon.Config) (*substratecommon.Block, error) {
+	if err := a.authorizer.Authorize(ctx, "QueryBlock", configs); err != nil {
+		return nil, err
+	}
+	if err := a.applyCreatorAttributes(ctx); err != nil {
+		return nil, err
+	}
+	return a.underlying.QueryBlockCtx(ctx, blockNumber, configs...)
+}
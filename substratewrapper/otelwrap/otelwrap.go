@@ -0,0 +1,217 @@
+// Package otelwrap provides an OpenTelemetry tracing/metrics decorator for
+// substratewrapper.SubstrateInstanceWrapperCommon. It lives in its own module
+// so that the core substratecommon module stays free of the OTel
+// dependency.
+package otelwrap
+
+import (
+	"context"
+	"time"
+
+	"github.com/luthersystems/substratecommon"
+	"github.com/luthersystems/substratecommon/substratewrapper"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
+	"go.opentelemetry.io/otel/metric/instrument/syncint64"
+	"go.opentelemetry.io/otel/metric/unit"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/luthersystems/substratecommon/substratewrapper/otelwrap"
+
+type substrateInstanceWrapperObserved struct {
+	underlying substratewrapper.SubstrateInstanceWrapperCommon
+	tag        string
+
+	tracer trace.Tracer
+
+	latency     syncfloat64.Histogram
+	payloadSize syncint64.Histogram
+	results     syncint64.Counter
+}
+
+// NewSubstrateInstanceWrapperObserved wraps underlying so that Init, Call,
+// QueryInfo, QueryBlock, and Close emit spans and metrics via tp and mp. The
+// tag is purely descriptive (e.g. the plugin instance tag) and is attached to
+// every span/metric as an attribute; pass "" if unavailable.
+func NewSubstrateInstanceWrapperObserved(underlying substratewrapper.SubstrateInstanceWrapperCommon, tag string, tp trace.TracerProvider, mp metric.MeterProvider) substratewrapper.SubstrateInstanceWrapperCommon {
+	meter := mp.Meter(instrumentationName)
+
+	latency, _ := meter.SyncFloat64().Histogram(
+		"substratewrapper.call.duration",
+		instrument.WithDescription("Latency of substratewrapper calls, in milliseconds"),
+		instrument.WithUnit(unit.Milliseconds),
+	)
+	payloadSize, _ := meter.SyncInt64().Histogram(
+		"substratewrapper.call.payload_size",
+		instrument.WithDescription("Size of the flattened request params, in bytes"),
+		instrument.WithUnit(unit.Bytes),
+	)
+	results, _ := meter.SyncInt64().Counter(
+		"substratewrapper.call.result",
+		instrument.WithDescription("Count of substratewrapper calls partitioned by outcome"),
+	)
+
+	return &substrateInstanceWrapperObserved{
+		underlying:  underlying,
+		tag:         tag,
+		tracer:      tp.Tracer(instrumentationName),
+		latency:     latency,
+		payloadSize: payloadSize,
+		results:     results,
+	}
+}
+
+// flattenCtx recovers the context carried by substratecommon.WithContext, if
+// any, falling back to context.Background(). This is how spans from callers
+// of ContextCoherent stitch together with server-side spans even though
+// Init/Call/QueryInfo/QueryBlock don't take a context directly.
+func flattenCtx(configs ...substratecommon.Config) context.Context {
+	ctx, err := substratecommon.FlattenContext(configs...)
+	if err != nil {
+		return context.Background()
+	}
+	return ctx
+}
+
+// payloadSizeOf returns the size in bytes of the flattened params, or -1 if
+// it can't be derived from configs.
+func payloadSizeOf(configs ...substratecommon.Config) int64 {
+	fo, err := substratecommon.FlattenOptions(configs...)
+	if err != nil {
+		return -1
+	}
+	return int64(len(fo.Params))
+}
+
+func (o *substrateInstanceWrapperObserved) record(ctx context.Context, span trace.Span, start time.Time, method string, err error) {
+	elapsedMS := float64(time.Since(start)) / float64(time.Millisecond)
+	attrs := []attribute.KeyValue{
+		attribute.String("substrate.method", method),
+		attribute.String("substrate.tag", o.tag),
+	}
+
+	outcome := "ok"
+	if err != nil {
+		span.RecordError(err)
+		if o.underlying.IsTimeoutError(err) {
+			outcome = "timeout"
+		} else {
+			outcome = "error"
+		}
+	}
+	attrs = append(attrs, attribute.String("substrate.outcome", outcome))
+
+	o.latency.Record(ctx, elapsedMS, attrs...)
+	o.results.Add(ctx, 1, attrs...)
+	span.End()
+}
+
+func (o *substrateInstanceWrapperObserved) startSpan(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append([]attribute.KeyValue{
+		attribute.String("substrate.method", method),
+		attribute.String("substrate.tag", o.tag),
+	}, attrs...)
+	return o.tracer.Start(ctx, "substrate."+method, trace.WithAttributes(attrs...))
+}
+
+func (o *substrateInstanceWrapperObserved) Close() error {
+	return o.CloseCtx(context.Background())
+}
+
+func (o *substrateInstanceWrapperObserved) CloseCtx(ctx context.Context) error {
+	start := time.Now()
+	ctx, span := o.startSpan(ctx, "Close")
+	err := o.underlying.CloseCtx(ctx)
+	o.record(ctx, span, start, "Close", err)
+	return err
+}
+
+func (o *substrateInstanceWrapperObserved) HealthCheck(x int) (int, error) {
+	return o.underlying.HealthCheck(x)
+}
+
+func (o *substrateInstanceWrapperObserved) HealthCheckCtx(ctx context.Context, x int) (int, error) {
+	return o.underlying.HealthCheckCtx(ctx, x)
+}
+
+func (o *substrateInstanceWrapperObserved) NewCoherent() substratewrapper.SubstrateInstanceWrapperCommon {
+	return substratewrapper.NewSubstrateInstanceWrapperCoherent(o)
+}
+
+func (o *substrateInstanceWrapperObserved) NewContextCoherent() substratewrapper.SubstrateInstanceWrapperCommon {
+	return substratewrapper.NewSubstrateInstanceWrapperContextCoherent(o)
+}
+
+func (o *substrateInstanceWrapperObserved) IsTimeoutError(err error) bool {
+	return o.underlying.IsTimeoutError(err)
+}
+
+func (o *substrateInstanceWrapperObserved) GetLastTransactionID() string {
+	return o.underlying.GetLastTransactionID()
+}
+
+func (o *substrateInstanceWrapperObserved) Upcast() *substratewrapper.SubstrateInstanceWrapperCommon {
+	var common substratewrapper.SubstrateInstanceWrapperCommon = o
+	return &common
+}
+
+func (o *substrateInstanceWrapperObserved) Init(phylum string, configs ...substratecommon.Config) error {
+	return o.InitCtx(flattenCtx(configs...), phylum, configs...)
+}
+
+func (o *substrateInstanceWrapperObserved) InitCtx(ctx context.Context, phylum string, configs ...substratecommon.Config) error {
+	start := time.Now()
+	ctx, span := o.startSpan(ctx, "Init", attribute.String("substrate.phylum", phylum))
+	err := o.underlying.InitCtx(ctx, phylum, configs...)
+	o.record(ctx, span, start, "Init", err)
+	return err
+}
+
+func (o *substrateInstanceWrapperObserved) Call(method string, configs ...substratecommon.Config) (*substratecommon.Response, error) {
+	return o.CallCtx(flattenCtx(configs...), method, configs...)
+}
+
+func (o *substrateInstanceWrapperObserved) CallCtx(ctx context.Context, method string, configs ...substratecommon.Config) (*substratecommon.Response, error) {
+	start := time.Now()
+	ctx, span := o.startSpan(ctx, "Call",
+		attribute.String("substrate.call_method", method),
+		attribute.String("substrate.dependent_tx_id", o.underlying.GetLastTransactionID()),
+	)
+	if size := payloadSizeOf(configs...); size >= 0 {
+		o.payloadSize.Record(ctx, size, attribute.String("substrate.call_method", method))
+	}
+	resp, err := o.underlying.CallCtx(ctx, method, configs...)
+	if resp != nil {
+		span.SetAttributes(attribute.String("substrate.transaction_id", resp.TransactionID))
+	}
+	o.record(ctx, span, start, "Call", err)
+	return resp, err
+}
+
+func (o *substrateInstanceWrapperObserved) QueryInfo(configs ...substratecommon.Config) (uint64, error) {
+	return o.QueryInfoCtx(flattenCtx(configs...), configs...)
+}
+
+func (o *substrateInstanceWrapperObserved) QueryInfoCtx(ctx context.Context, configs ...substratecommon.Config) (uint64, error) {
+	start := time.Now()
+	ctx, span := o.startSpan(ctx, "QueryInfo")
+	height, err := o.underlying.QueryInfoCtx(ctx, configs...)
+	o.record(ctx, span, start, "QueryInfo", err)
+	return height, err
+}
+
+func (o *substrateInstanceWrapperObserved) QueryBlock(blockNumber uint64, configs ...substratecommon.Config) (*substratecommon.Block, error) {
+	return o.QueryBlockCtx(flattenCtx(configs...), blockNumber, configs...)
+}
+
+func (o *substrateInstanceWrapperObserved) QueryBlockCtx(ctx context.Context, blockNumber uint64, configs ...substratecommon.Config) (*substratecommon.Block, error) {
+	start := time.Now()
+	ctx, span := o.startSpan(ctx, "QueryBlock", attribute.Int64("substrate.block_number", int64(blockNumber)))
+	block, err := o.underlying.QueryBlockCtx(ctx, blockNumber, configs...)
+	o.record(ctx, span, start, "QueryBlock", err)
+	return block, err
+}
@@ -0,0 +1,335 @@
+package substratewrapper
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/luthersystems/substratecommon"
+)
+
+// RetryPolicy controls how substrateInstanceWrapperRetrying retries Call,
+// QueryInfo, and QueryBlock, and how its circuit breaker trips.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times a request is attempted,
+	// including the first try. Values <= 1 disable retries entirely.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the exponentially-growing delay between retries.
+	MaxBackoff time.Duration
+	// Multiplier scales the backoff after each failed attempt. Values <= 1
+	// are treated as 2.
+	Multiplier float64
+	// Jitter is the fraction (0.0-1.0) of the computed backoff that is
+	// randomized, to avoid thundering-herd retries.
+	Jitter float64
+
+	// CircuitBreakerThreshold is the number of consecutive failures, across
+	// calls, after which the breaker opens and fails fast. A value <= 0
+	// disables the breaker.
+	CircuitBreakerThreshold int
+	// CircuitBreakerCooldown is how long the breaker stays open before
+	// allowing a single half-open trial request through.
+	CircuitBreakerCooldown time.Duration
+}
+
+func (p RetryPolicy) maxAttempts() int {
+	if p.MaxAttempts <= 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+func (p RetryPolicy) multiplier() float64 {
+	if p.Multiplier <= 1 {
+		return 2
+	}
+	return p.Multiplier
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.InitialBackoff
+	for i := 0; i < attempt; i++ {
+		d = time.Duration(float64(d) * p.multiplier())
+		if p.MaxBackoff > 0 && d > p.MaxBackoff {
+			d = p.MaxBackoff
+			break
+		}
+	}
+	if p.Jitter > 0 {
+		jitter := p.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		d = d - time.Duration(float64(d)*jitter*rand.Float64())
+	}
+	return d
+}
+
+// errCircuitOpen is returned when the circuit breaker is refusing requests.
+var errCircuitOpen = fmt.Errorf("substratewrapper: circuit breaker open")
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// circuitBreaker opens after a configurable number of consecutive failures
+// and half-opens after a cool-down, allowing a single trial request through.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	state     circuitState
+	failures  int
+	threshold int
+	cooldown  time.Duration
+	openedAt  time.Time
+	// trialInFlight is true while a half-open trial request is outstanding,
+	// so only one caller at a time is let through to probe the breaker.
+	trialInFlight bool
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown}
+}
+
+func (b *circuitBreaker) allow() bool {
+	if b.threshold <= 0 {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case circuitOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = circuitHalfOpen
+		b.trialInFlight = true
+		return true
+	case circuitHalfOpen:
+		if b.trialInFlight {
+			return false
+		}
+		b.trialInFlight = true
+		return true
+	default:
+		return true
+	}
+}
+
+func (b *circuitBreaker) recordSuccess() {
+	if b.threshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.state = circuitClosed
+	b.trialInFlight = false
+}
+
+func (b *circuitBreaker) recordFailure() {
+	if b.threshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.trialInFlight = false
+	if b.state == circuitHalfOpen || b.failures >= b.threshold {
+		b.state = circuitOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// sleepCtx waits for d, returning early with ctx.Err() if ctx is done first.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+type substrateInstanceWrapperRetrying struct {
+	underlying SubstrateInstanceWrapperCommon
+	policy     RetryPolicy
+	breaker    *circuitBreaker
+
+	mu        sync.Mutex
+	dependent string
+}
+
+// NewSubstrateInstanceWrapperRetrying returns a decorator that automatically
+// retries Call, QueryInfo, and QueryBlock against policy, classifying
+// retryable failures with the underlying wrapper's IsTimeoutError. It
+// composes with the coherent wrappers the same way they compose with each
+// other, e.g. NewSubstrateInstanceWrapperRetrying(underlying, policy).NewCoherent().
+func NewSubstrateInstanceWrapperRetrying(underlying SubstrateInstanceWrapperCommon, policy RetryPolicy) SubstrateInstanceWrapperCommon {
+	return &substrateInstanceWrapperRetrying{
+		underlying: underlying,
+		policy:     policy,
+		breaker:    newCircuitBreaker(policy.CircuitBreakerThreshold, policy.CircuitBreakerCooldown),
+	}
+}
+
+func (r *substrateInstanceWrapperRetrying) Close() error {
+	return r.underlying.Close()
+}
+
+func (r *substrateInstanceWrapperRetrying) CloseCtx(ctx context.Context) error {
+	return r.underlying.CloseCtx(ctx)
+}
+
+func (r *substrateInstanceWrapperRetrying) HealthCheck(x int) (int, error) {
+	return r.underlying.HealthCheck(x)
+}
+
+func (r *substrateInstanceWrapperRetrying) HealthCheckCtx(ctx context.Context, x int) (int, error) {
+	return r.underlying.HealthCheckCtx(ctx, x)
+}
+
+func (r *substrateInstanceWrapperRetrying) NewCoherent() SubstrateInstanceWrapperCommon {
+	return NewSubstrateInstanceWrapperCoherent(r)
+}
+
+func (r *substrateInstanceWrapperRetrying) NewContextCoherent() SubstrateInstanceWrapperCommon {
+	return NewSubstrateInstanceWrapperContextCoherent(r)
+}
+
+func (r *substrateInstanceWrapperRetrying) IsTimeoutError(err error) bool {
+	return r.underlying.IsTimeoutError(err)
+}
+
+func (r *substrateInstanceWrapperRetrying) Init(phylum string, configs ...substratecommon.Config) error {
+	return r.underlying.Init(phylum, configs...)
+}
+
+func (r *substrateInstanceWrapperRetrying) InitCtx(ctx context.Context, phylum string, configs ...substratecommon.Config) error {
+	return r.underlying.InitCtx(ctx, phylum, configs...)
+}
+
+func (r *substrateInstanceWrapperRetrying) GetLastTransactionID() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.dependent
+}
+
+func (r *substrateInstanceWrapperRetrying) Upcast() *SubstrateInstanceWrapperCommon {
+	var common SubstrateInstanceWrapperCommon = r
+	return &common
+}
+
+func (r *substrateInstanceWrapperRetrying) Call(method string, configs ...substratecommon.Config) (*substratecommon.Response, error) {
+	return r.CallCtx(flattenCtx(configs...), method, configs...)
+}
+
+func (r *substrateInstanceWrapperRetrying) CallCtx(ctx context.Context, method string, configs ...substratecommon.Config) (*substratecommon.Response, error) {
+	r.mu.Lock()
+	dependent := r.dependent
+	r.mu.Unlock()
+
+	configsBase := configs
+	if dependent != "" {
+		configsBase = append(configsBase, substratecommon.WithConditionalDependentTxID(dependent))
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < r.policy.maxAttempts(); attempt++ {
+		if !r.breaker.allow() {
+			return nil, errCircuitOpen
+		}
+
+		resp, err := r.underlying.CallCtx(ctx, method, configsBase...)
+		if err == nil {
+			r.breaker.recordSuccess()
+			r.mu.Lock()
+			r.dependent = resp.TransactionID
+			r.mu.Unlock()
+			return resp, nil
+		}
+
+		r.breaker.recordFailure()
+		lastErr = err
+
+		if resp != nil && resp.TransactionID != "" {
+			// The request was actually submitted before failing; retrying
+			// here risks double-submission, so surface the error as-is.
+			return resp, err
+		}
+		if attempt == r.policy.maxAttempts()-1 || !r.underlying.IsTimeoutError(err) {
+			return nil, err
+		}
+		if sleepErr := sleepCtx(ctx, r.policy.backoff(attempt)); sleepErr != nil {
+			return nil, sleepErr
+		}
+	}
+	return nil, lastErr
+}
+
+func (r *substrateInstanceWrapperRetrying) QueryInfo(configs ...substratecommon.Config) (uint64, error) {
+	return r.QueryInfoCtx(flattenCtx(configs...), configs...)
+}
+
+func (r *substrateInstanceWrapperRetrying) QueryInfoCtx(ctx context.Context, configs ...substratecommon.Config) (uint64, error) {
+	var lastErr error
+	for attempt := 0; attempt < r.policy.maxAttempts(); attempt++ {
+		if !r.breaker.allow() {
+			return 0, errCircuitOpen
+		}
+		height, err := r.underlying.QueryInfoCtx(ctx, configs...)
+		if err == nil {
+			r.breaker.recordSuccess()
+			return height, nil
+		}
+		r.breaker.recordFailure()
+		lastErr = err
+		if attempt == r.policy.maxAttempts()-1 || !r.underlying.IsTimeoutError(err) {
+			return 0, err
+		}
+		if sleepErr := sleepCtx(ctx, r.policy.backoff(attempt)); sleepErr != nil {
+			return 0, sleepErr
+		}
+	}
+	return 0, lastErr
+}
+
+func (r *substrateInstanceWrapperRetrying) QueryBlock(blockNumber uint64, configs ...substratecommon.Config) (*substratecommon.Block, error) {
+	return r.QueryBlockCtx(flattenCtx(configs...), blockNumber, configs...)
+}
+
+func (r *substrateInstanceWrapperRetrying) QueryBlockCtx(ctx context.Context, blockNumber uint64, configs ...substratecommon.Config) (*substratecommon.Block, error) {
+	var lastErr error
+	for attempt := 0; attempt < r.policy.maxAttempts(); attempt++ {
+		if !r.breaker.allow() {
+			return nil, errCircuitOpen
+		}
+		block, err := r.underlying.QueryBlockCtx(ctx, blockNumber, configs...)
+		if err == nil {
+			r.breaker.recordSuccess()
+			return block, nil
+		}
+		r.breaker.recordFailure()
+		lastErr = err
+		if attempt == r.policy.maxAttempts()-1 || !r.underlying.IsTimeoutError(err) {
+			return nil, err
+		}
+		if sleepErr := sleepCtx(ctx, r.policy.backoff(attempt)); sleepErr != nil {
+			return nil, sleepErr
+		}
+	}
+	return nil, lastErr
+}
@@ -0,0 +1,106 @@
+package substratewrapper
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerDisabled(t *testing.T) {
+	b := newCircuitBreaker(0, time.Minute)
+	for i := 0; i < 5; i++ {
+		if !b.allow() {
+			t.Fatal("a breaker with threshold <= 0 must always allow")
+		}
+	}
+}
+
+func TestCircuitBreakerOpensAfterThreshold(t *testing.T) {
+	b := newCircuitBreaker(2, time.Hour)
+	if !b.allow() {
+		t.Fatal("breaker should start closed")
+	}
+	b.recordFailure()
+	if !b.allow() {
+		t.Fatal("breaker should still be closed below threshold")
+	}
+	b.recordFailure()
+	if b.allow() {
+		t.Fatal("breaker should open once failures reach threshold")
+	}
+}
+
+func TestCircuitBreakerHalfOpenAllowsSingleTrial(t *testing.T) {
+	b := newCircuitBreaker(1, time.Millisecond)
+	b.recordFailure()
+	if b.allow() {
+		t.Fatal("breaker should be open immediately after tripping")
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	if !b.allow() {
+		t.Fatal("breaker should let exactly one half-open trial through")
+	}
+	for i := 0; i < 5; i++ {
+		if b.allow() {
+			t.Fatal("only one trial request may be in flight while half-open")
+		}
+	}
+}
+
+func TestCircuitBreakerHalfOpenConcurrentTrialIsExclusive(t *testing.T) {
+	b := newCircuitBreaker(1, time.Millisecond)
+	b.recordFailure()
+	time.Sleep(5 * time.Millisecond)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	allowed := 0
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if b.allow() {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if allowed != 1 {
+		t.Fatalf("expected exactly 1 caller let through during half-open, got %d", allowed)
+	}
+}
+
+func TestCircuitBreakerRecordSuccessClosesAndClearsTrial(t *testing.T) {
+	b := newCircuitBreaker(1, time.Millisecond)
+	b.recordFailure()
+	time.Sleep(5 * time.Millisecond)
+	if !b.allow() {
+		t.Fatal("expected trial to be allowed")
+	}
+	b.recordSuccess()
+	if b.state != circuitClosed {
+		t.Fatalf("expected breaker to close after a successful trial, got state %v", b.state)
+	}
+	if !b.allow() {
+		t.Fatal("breaker should allow requests once closed")
+	}
+}
+
+func TestCircuitBreakerRecordFailureReopensFromHalfOpen(t *testing.T) {
+	b := newCircuitBreaker(1, time.Millisecond)
+	b.recordFailure()
+	time.Sleep(5 * time.Millisecond)
+	if !b.allow() {
+		t.Fatal("expected trial to be allowed")
+	}
+	b.recordFailure()
+	if b.state != circuitOpen {
+		t.Fatalf("expected a failed trial to reopen the breaker, got state %v", b.state)
+	}
+	if b.allow() {
+		t.Fatal("breaker should be open again immediately after a failed trial")
+	}
+}
@@ -14,15 +14,26 @@ type SubstrateWrapper interface {
 
 type SubstrateInstanceWrapperCommon interface {
 	io.Closer
+	CloseCtx(ctx context.Context) error
 	HealthCheck(x int) (int, error)
+	HealthCheckCtx(ctx context.Context, x int) (int, error)
 	NewCoherent() SubstrateInstanceWrapperCommon
 	NewContextCoherent() SubstrateInstanceWrapperCommon
 	IsTimeoutError(err error) bool
 	Init(phylum string, configs ...substratecommon.Config) error
+	InitCtx(ctx context.Context, phylum string, configs ...substratecommon.Config) error
 	Call(method string, configs ...substratecommon.Config) (*substratecommon.Response, error)
+	CallCtx(ctx context.Context, method string, configs ...substratecommon.Config) (*substratecommon.Response, error)
 	QueryInfo(configs ...substratecommon.Config) (uint64, error)
+	QueryInfoCtx(ctx context.Context, configs ...substratecommon.Config) (uint64, error)
 	QueryBlock(blockNumber uint64, configs ...substratecommon.Config) (*substratecommon.Block, error)
+	QueryBlockCtx(ctx context.Context, blockNumber uint64, configs ...substratecommon.Config) (*substratecommon.Block, error)
 	GetLastTransactionID() string
+	// Upcast narrows the receiver down to *SubstrateInstanceWrapperCommon,
+	// for packages like batch and private that only need the shared
+	// surface and would otherwise have to import whichever concrete
+	// wrapper (RPC, Mock, Coherent, ...) the caller happens to be holding.
+	Upcast() *SubstrateInstanceWrapperCommon
 }
 
 type SubstrateInstanceWrapperRPC interface {
@@ -33,6 +44,20 @@ type SubstrateInstanceWrapperMock interface {
 	SubstrateInstanceWrapperCommon
 	SetCreatorWithAttributes(creator string, attrs map[string]string) error
 	Snapshot() ([]byte, error)
+	// CloseMock is Close, named explicitly for call sites that only ever
+	// hold a mock instance and want that reflected in the method name.
+	CloseMock() error
+}
+
+// flattenCtx recovers the context carried by substratecommon.WithContext, if
+// any, falling back to context.Background() for callers that haven't
+// migrated to the *Ctx methods yet.
+func flattenCtx(configs ...substratecommon.Config) context.Context {
+	ctx, err := substratecommon.FlattenContext(configs...)
+	if err != nil {
+		return context.Background()
+	}
+	return ctx
 }
 
 type substrateWrapper struct {
@@ -70,11 +95,19 @@ func (sw *substrateWrapper) NewMockFrom(name string, phylumVersion string, blob
 }
 
 func (siwr *substrateInstanceWrapperRPC) Close() error {
-	return siwr.substrate.CloseRPC(siwr.tag)
+	return siwr.CloseCtx(context.Background())
+}
+
+func (siwr *substrateInstanceWrapperRPC) CloseCtx(ctx context.Context) error {
+	return siwr.substrate.CloseRPCCtx(ctx, siwr.tag)
 }
 
 func (siwr *substrateInstanceWrapperRPC) HealthCheck(x int) (int, error) {
-	return siwr.substrate.HealthCheck(x)
+	return siwr.HealthCheckCtx(context.Background(), x)
+}
+
+func (siwr *substrateInstanceWrapperRPC) HealthCheckCtx(ctx context.Context, x int) (int, error) {
+	return siwr.substrate.HealthCheckCtx(ctx, x)
 }
 
 func (siwr *substrateInstanceWrapperRPC) NewCoherent() SubstrateInstanceWrapperCommon {
@@ -90,47 +123,76 @@ func (siwr *substrateInstanceWrapperRPC) IsTimeoutError(err error) bool {
 }
 
 func (siwr *substrateInstanceWrapperRPC) Init(phylum string, configs ...substratecommon.Config) error {
+	return siwr.InitCtx(flattenCtx(configs...), phylum, configs...)
+}
+
+func (siwr *substrateInstanceWrapperRPC) InitCtx(ctx context.Context, phylum string, configs ...substratecommon.Config) error {
 	fo, err := substratecommon.FlattenOptions(configs...)
 	if err != nil {
 		return err
 	}
-	return siwr.substrate.Init(siwr.tag, phylum, fo)
+	return siwr.substrate.InitCtx(ctx, siwr.tag, phylum, fo)
 }
 
 func (siwr *substrateInstanceWrapperRPC) Call(method string, configs ...substratecommon.Config) (*substratecommon.Response, error) {
+	return siwr.CallCtx(flattenCtx(configs...), method, configs...)
+}
+
+func (siwr *substrateInstanceWrapperRPC) CallCtx(ctx context.Context, method string, configs ...substratecommon.Config) (*substratecommon.Response, error) {
 	fo, err := substratecommon.FlattenOptions(configs...)
 	if err != nil {
 		return nil, err
 	}
-	return siwr.substrate.Call(siwr.tag, method, fo)
+	return siwr.substrate.CallCtx(ctx, siwr.tag, method, fo)
 }
 
 func (siwr *substrateInstanceWrapperRPC) QueryInfo(configs ...substratecommon.Config) (uint64, error) {
+	return siwr.QueryInfoCtx(flattenCtx(configs...), configs...)
+}
+
+func (siwr *substrateInstanceWrapperRPC) QueryInfoCtx(ctx context.Context, configs ...substratecommon.Config) (uint64, error) {
 	fo, err := substratecommon.FlattenOptions(configs...)
 	if err != nil {
 		return 0, err
 	}
-	return siwr.substrate.QueryInfo(siwr.tag, fo)
+	return siwr.substrate.QueryInfoCtx(ctx, siwr.tag, fo)
 }
 
 func (siwr *substrateInstanceWrapperRPC) QueryBlock(blockNumber uint64, configs ...substratecommon.Config) (*substratecommon.Block, error) {
+	return siwr.QueryBlockCtx(flattenCtx(configs...), blockNumber, configs...)
+}
+
+func (siwr *substrateInstanceWrapperRPC) QueryBlockCtx(ctx context.Context, blockNumber uint64, configs ...substratecommon.Config) (*substratecommon.Block, error) {
 	fo, err := substratecommon.FlattenOptions(configs...)
 	if err != nil {
 		return nil, err
 	}
-	return siwr.substrate.QueryBlock(siwr.tag, blockNumber, fo)
+	return siwr.substrate.QueryBlockCtx(ctx, siwr.tag, blockNumber, fo)
 }
 
 func (siwr *substrateInstanceWrapperRPC) GetLastTransactionID() string {
 	return ""
 }
 
+func (siwr *substrateInstanceWrapperRPC) Upcast() *SubstrateInstanceWrapperCommon {
+	var common SubstrateInstanceWrapperCommon = siwr
+	return &common
+}
+
 func (siwm *substrateInstanceWrapperMock) Close() error {
-	return siwm.substrate.CloseMock(siwm.tag)
+	return siwm.CloseCtx(context.Background())
+}
+
+func (siwm *substrateInstanceWrapperMock) CloseCtx(ctx context.Context) error {
+	return siwm.substrate.CloseMockCtx(ctx, siwm.tag)
 }
 
 func (siwm *substrateInstanceWrapperMock) HealthCheck(x int) (int, error) {
-	return siwm.substrate.HealthCheck(x)
+	return siwm.HealthCheckCtx(context.Background(), x)
+}
+
+func (siwm *substrateInstanceWrapperMock) HealthCheckCtx(ctx context.Context, x int) (int, error) {
+	return siwm.substrate.HealthCheckCtx(ctx, x)
 }
 
 func (siwm *substrateInstanceWrapperMock) NewCoherent() SubstrateInstanceWrapperCommon {
@@ -154,41 +216,66 @@ func (siwm *substrateInstanceWrapperMock) Snapshot() ([]byte, error) {
 }
 
 func (siwm *substrateInstanceWrapperMock) Init(phylum string, configs ...substratecommon.Config) error {
+	return siwm.InitCtx(flattenCtx(configs...), phylum, configs...)
+}
+
+func (siwm *substrateInstanceWrapperMock) InitCtx(ctx context.Context, phylum string, configs ...substratecommon.Config) error {
 	fo, err := substratecommon.FlattenOptions(configs...)
 	if err != nil {
 		return err
 	}
-	return siwm.substrate.Init(siwm.tag, phylum, fo)
+	return siwm.substrate.InitCtx(ctx, siwm.tag, phylum, fo)
 }
 
 func (siwm *substrateInstanceWrapperMock) Call(method string, configs ...substratecommon.Config) (*substratecommon.Response, error) {
+	return siwm.CallCtx(flattenCtx(configs...), method, configs...)
+}
+
+func (siwm *substrateInstanceWrapperMock) CallCtx(ctx context.Context, method string, configs ...substratecommon.Config) (*substratecommon.Response, error) {
 	fo, err := substratecommon.FlattenOptions(configs...)
 	if err != nil {
 		return nil, err
 	}
-	return siwm.substrate.Call(siwm.tag, method, fo)
+	return siwm.substrate.CallCtx(ctx, siwm.tag, method, fo)
 }
 
 func (siwm *substrateInstanceWrapperMock) QueryInfo(configs ...substratecommon.Config) (uint64, error) {
+	return siwm.QueryInfoCtx(flattenCtx(configs...), configs...)
+}
+
+func (siwm *substrateInstanceWrapperMock) QueryInfoCtx(ctx context.Context, configs ...substratecommon.Config) (uint64, error) {
 	fo, err := substratecommon.FlattenOptions(configs...)
 	if err != nil {
 		return 0, err
 	}
-	return siwm.substrate.QueryInfo(siwm.tag, fo)
+	return siwm.substrate.QueryInfoCtx(ctx, siwm.tag, fo)
 }
 
 func (siwm *substrateInstanceWrapperMock) QueryBlock(blockNumber uint64, configs ...substratecommon.Config) (*substratecommon.Block, error) {
+	return siwm.QueryBlockCtx(flattenCtx(configs...), blockNumber, configs...)
+}
+
+func (siwm *substrateInstanceWrapperMock) QueryBlockCtx(ctx context.Context, blockNumber uint64, configs ...substratecommon.Config) (*substratecommon.Block, error) {
 	fo, err := substratecommon.FlattenOptions(configs...)
 	if err != nil {
 		return nil, err
 	}
-	return siwm.substrate.QueryBlock(siwm.tag, blockNumber, fo)
+	return siwm.substrate.QueryBlockCtx(ctx, siwm.tag, blockNumber, fo)
 }
 
 func (siwm *substrateInstanceWrapperMock) GetLastTransactionID() string {
 	return ""
 }
 
+func (siwm *substrateInstanceWrapperMock) CloseMock() error {
+	return siwm.Close()
+}
+
+func (siwm *substrateInstanceWrapperMock) Upcast() *SubstrateInstanceWrapperCommon {
+	var common SubstrateInstanceWrapperCommon = siwm
+	return &common
+}
+
 type substrateInstanceWrapperCoherent struct {
 	underlying SubstrateInstanceWrapperCommon
 	dependent  string
@@ -198,10 +285,18 @@ func (siwc *substrateInstanceWrapperCoherent) Close() error {
 	return siwc.underlying.Close()
 }
 
+func (siwc *substrateInstanceWrapperCoherent) CloseCtx(ctx context.Context) error {
+	return siwc.underlying.CloseCtx(ctx)
+}
+
 func (siwc *substrateInstanceWrapperCoherent) HealthCheck(x int) (int, error) {
 	return siwc.underlying.HealthCheck(x)
 }
 
+func (siwc *substrateInstanceWrapperCoherent) HealthCheckCtx(ctx context.Context, x int) (int, error) {
+	return siwc.underlying.HealthCheckCtx(ctx, x)
+}
+
 func (siwc *substrateInstanceWrapperCoherent) NewCoherent() SubstrateInstanceWrapperCommon {
 	return NewSubstrateInstanceWrapperCoherent(siwc)
 }
@@ -218,12 +313,20 @@ func (siwc *substrateInstanceWrapperCoherent) Init(phylum string, configs ...sub
 	return siwc.underlying.Init(phylum, configs...)
 }
 
+func (siwc *substrateInstanceWrapperCoherent) InitCtx(ctx context.Context, phylum string, configs ...substratecommon.Config) error {
+	return siwc.underlying.InitCtx(ctx, phylum, configs...)
+}
+
 func (siwc *substrateInstanceWrapperCoherent) Call(method string, configs ...substratecommon.Config) (*substratecommon.Response, error) {
+	return siwc.CallCtx(flattenCtx(configs...), method, configs...)
+}
+
+func (siwc *substrateInstanceWrapperCoherent) CallCtx(ctx context.Context, method string, configs ...substratecommon.Config) (*substratecommon.Response, error) {
 	configs2 := configs
 	if siwc.dependent != "" {
 		configs2 = append(configs2, substratecommon.WithConditionalDependentTxID(siwc.dependent))
 	}
-	resp, err := siwc.underlying.Call(method, configs2...)
+	resp, err := siwc.underlying.CallCtx(ctx, method, configs2...)
 	if err != nil {
 		return nil, err
 	}
@@ -235,14 +338,27 @@ func (siwc *substrateInstanceWrapperCoherent) QueryInfo(configs ...substratecomm
 	return siwc.underlying.QueryInfo(configs...)
 }
 
+func (siwc *substrateInstanceWrapperCoherent) QueryInfoCtx(ctx context.Context, configs ...substratecommon.Config) (uint64, error) {
+	return siwc.underlying.QueryInfoCtx(ctx, configs...)
+}
+
 func (siwc *substrateInstanceWrapperCoherent) QueryBlock(blockNumber uint64, configs ...substratecommon.Config) (*substratecommon.Block, error) {
 	return siwc.underlying.QueryBlock(blockNumber, configs...)
 }
 
+func (siwc *substrateInstanceWrapperCoherent) QueryBlockCtx(ctx context.Context, blockNumber uint64, configs ...substratecommon.Config) (*substratecommon.Block, error) {
+	return siwc.underlying.QueryBlockCtx(ctx, blockNumber, configs...)
+}
+
 func (siwc *substrateInstanceWrapperCoherent) GetLastTransactionID() string {
 	return siwc.dependent
 }
 
+func (siwc *substrateInstanceWrapperCoherent) Upcast() *SubstrateInstanceWrapperCommon {
+	var common SubstrateInstanceWrapperCommon = siwc
+	return &common
+}
+
 func NewSubstrateInstanceWrapperCoherent(siwc SubstrateInstanceWrapperCommon) SubstrateInstanceWrapperCommon {
 	return &substrateInstanceWrapperCoherent{underlying: siwc}
 }
@@ -258,10 +374,16 @@ type dependentWrapper struct {
 	dependent string
 }
 
+// ContextWithTransactionID returns a context that substrateInstanceWrapperContextCoherent.CallCtx
+// will use to track the dependent transaction ID across calls made with it.
+// Callers pass the returned context straight to a *Ctx method; no
+// substratecommon.Config smuggling is involved.
 func ContextWithTransactionID(ctx context.Context) context.Context {
 	return context.WithValue(ctx, dependentKey, &dependentWrapper{})
 }
 
+// GetContextTransactionID returns the dependent transaction ID tracked by a
+// context previously returned from ContextWithTransactionID.
 func GetContextTransactionID(ctx context.Context) string {
 	dw, ok := ctx.Value(dependentKey).(*dependentWrapper)
 	if ok {
@@ -278,10 +400,18 @@ func (siwc *substrateInstanceWrapperContextCoherent) Close() error {
 	return siwc.underlying.Close()
 }
 
+func (siwc *substrateInstanceWrapperContextCoherent) CloseCtx(ctx context.Context) error {
+	return siwc.underlying.CloseCtx(ctx)
+}
+
 func (siwc *substrateInstanceWrapperContextCoherent) HealthCheck(x int) (int, error) {
 	return siwc.underlying.HealthCheck(x)
 }
 
+func (siwc *substrateInstanceWrapperContextCoherent) HealthCheckCtx(ctx context.Context, x int) (int, error) {
+	return siwc.underlying.HealthCheckCtx(ctx, x)
+}
+
 func (siwc *substrateInstanceWrapperContextCoherent) NewCoherent() SubstrateInstanceWrapperCommon {
 	return NewSubstrateInstanceWrapperCoherent(siwc)
 }
@@ -298,17 +428,24 @@ func (siwc *substrateInstanceWrapperContextCoherent) Init(phylum string, configs
 	return siwc.underlying.Init(phylum, configs...)
 }
 
+func (siwc *substrateInstanceWrapperContextCoherent) InitCtx(ctx context.Context, phylum string, configs ...substratecommon.Config) error {
+	return siwc.underlying.InitCtx(ctx, phylum, configs...)
+}
+
 func (siwc *substrateInstanceWrapperContextCoherent) Call(method string, configs ...substratecommon.Config) (*substratecommon.Response, error) {
+	return siwc.CallCtx(flattenCtx(configs...), method, configs...)
+}
+
+// CallCtx takes ctx directly rather than recovering it from configs, so the
+// dependent-tx dance below no longer needs to smuggle it through
+// substratecommon.WithContext/FlattenContext.
+func (siwc *substrateInstanceWrapperContextCoherent) CallCtx(ctx context.Context, method string, configs ...substratecommon.Config) (*substratecommon.Response, error) {
 	configs2 := configs
-	ctx, err := substratecommon.FlattenContext(configs...)
-	if err != nil {
-		ctx = context.Background()
-	}
 	dw, ok := ctx.Value(dependentKey).(*dependentWrapper)
 	if ok && dw.dependent != "" {
 		configs2 = append(configs2, substratecommon.WithDependentTxID(dw.dependent))
 	}
-	resp, err := siwc.underlying.Call(method, configs2...)
+	resp, err := siwc.underlying.CallCtx(ctx, method, configs2...)
 	if err != nil {
 		return nil, err
 	}
@@ -322,14 +459,27 @@ func (siwc *substrateInstanceWrapperContextCoherent) QueryInfo(configs ...substr
 	return siwc.underlying.QueryInfo(configs...)
 }
 
+func (siwc *substrateInstanceWrapperContextCoherent) QueryInfoCtx(ctx context.Context, configs ...substratecommon.Config) (uint64, error) {
+	return siwc.underlying.QueryInfoCtx(ctx, configs...)
+}
+
 func (siwc *substrateInstanceWrapperContextCoherent) QueryBlock(blockNumber uint64, configs ...substratecommon.Config) (*substratecommon.Block, error) {
 	return siwc.underlying.QueryBlock(blockNumber, configs...)
 }
 
+func (siwc *substrateInstanceWrapperContextCoherent) QueryBlockCtx(ctx context.Context, blockNumber uint64, configs ...substratecommon.Config) (*substratecommon.Block, error) {
+	return siwc.underlying.QueryBlockCtx(ctx, blockNumber, configs...)
+}
+
 func (siwc *substrateInstanceWrapperContextCoherent) GetLastTransactionID() string {
 	return ""
 }
 
+func (siwc *substrateInstanceWrapperContextCoherent) Upcast() *SubstrateInstanceWrapperCommon {
+	var common SubstrateInstanceWrapperCommon = siwc
+	return &common
+}
+
 func NewSubstrateInstanceWrapperContextCoherent(siwc SubstrateInstanceWrapperCommon) SubstrateInstanceWrapperCommon {
 	return &substrateInstanceWrapperContextCoherent{underlying: siwc}
 }